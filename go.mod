@@ -4,4 +4,10 @@ go 1.22.0
 
 require github.com/raiecs v0.0.0
 
+require (
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1 // indirect
+	go.opentelemetry.io/otel v1.24.0 // indirect
+	go.opentelemetry.io/otel/trace v1.24.0 // indirect
+)
+
 replace github.com/raiecs => ./raiecs
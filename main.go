@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	ecsgoclient "github.com/raiecs"
@@ -11,16 +13,18 @@ import (
 func main() {
 	fmt.Println("Hello!")
 	fmt.Println("Creating ECS Client Options")
-	env := ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_INTEGRATION
+	endpointEnv := ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_PRODUCTION
+	authEnv := ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_INTEGRATION
 	options := ecsgoclient.EcsClientOptions{
 		Client:       "ECS_Test_Agrawalsh",
 		ProjectTeams: []string{"ResponsibleAI"},
+		Environment:  &endpointEnv,
 		TargetFilters: map[string][]string{
 			"EnvironmentName": {"YourEnvironment"},
 			"ServiceName":     {"YourService"},
 		},
 		Logger:                    ECSLogger{},
-		AuthenticationEnvironment: &env,
+		AuthenticationEnvironment: &authEnv,
 		AuthenticationMethod:      ecsclientgowrapper.ECS_AUTHENTICATION_METHOD_USERASSIGNEDMANAGEDIDENTITY,
 		TenantId:                  "72f988bf-86f1-41af-91ab-2d7cd011db47",
 		ClientId:                  "add30c60-c1ac-43e6-992a-52c4da308a92",
@@ -32,18 +36,23 @@ func main() {
 	ecsClient, err = ecsgoclient.NewEcsClient(options)
 
 	if err != nil {
-		// throw error + log
+		fmt.Printf("Creating ECS client failed with error: %v", err.Error())
+		os.Exit(1)
 	}
 
 	// Step 2: Get new options registered with ECS
 	fmt.Println("Registering Monitor")
-	config_obj := registerOptionsMonitor(ecsClient)
-	old_config := Config{config: config_obj.config}
+	config_monitor, err := registerOptionsMonitor(ecsClient)
+	if err != nil {
+		fmt.Printf("Registering the monitor failed with error: %v", err.Error())
+		os.Exit(1)
+	}
+	old_config := config_monitor.Current()
 	for {
 		fmt.Println("Waiting for config...")
-		if old_config.config != config_obj.config {
-			fmt.Printf("New Config Recieved: %s", config_obj.config)
-			old_config.config = config_obj.config
+		if new_config := config_monitor.Current(); new_config != old_config {
+			fmt.Printf("New Config Recieved: %s", *new_config)
+			old_config = new_config
 		}
 		time.Sleep(15 * time.Second)
 	}
@@ -56,18 +65,6 @@ func (ecslogger ECSLogger) Log(logLevel ecsclientgowrapper.ECS_LOG_LEVEL, msg st
 	fmt.Printf("Received log. %v: %v \n", logLevel, msg)
 }
 
-type Config struct {
-	config string
-}
-
-func (o *Config) OnOptionsUpdateReceived(bytes []byte) error {
-	parsedOptions := string(bytes)
-	o.config = parsedOptions
-	return nil
-}
-
-func registerOptionsMonitor(client *ecsgoclient.EcsClient) *Config {
-	options := &Config{config: ""}
-	client.AddOptionsMonitorToEcsClient(options, "ResponsibleAI", "SampleOptions")
-	return options
+func registerOptionsMonitor(client *ecsgoclient.EcsClient) (*ecsgoclient.Monitor[json.RawMessage], error) {
+	return ecsgoclient.AddTypedMonitor[json.RawMessage](client, "ResponsibleAI", "SampleOptions")
 }
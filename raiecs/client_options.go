@@ -0,0 +1,27 @@
+package ecsgoclient
+
+import (
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// ClientOption configures the Go side of an EcsClient, like the metrics, independent of the ecs C library
+type ClientOption func(*EcsClient)
+
+// WithMetricsRecorder sets the MetricsRecorder the EcsClient reports its fetches, events and options updates to
+func WithMetricsRecorder(metricsRecorder MetricsRecorder) ClientOption {
+	return func(ecsClient *EcsClient) {
+		if metricsRecorder != nil {
+			ecsClient.metrics = metricsRecorder
+		}
+	}
+}
+
+// WithLogLevel drops the log messages of the EcsClient below minLevel, e.g. for clients created by NewEcsClientFromConfigGetter.
+// NewEcsClient already applies the LogLevel of its EcsClientOptions.
+func WithLogLevel(minLevel ecsclientgowrapper.ECS_LOG_LEVEL) ClientOption {
+	return func(ecsClient *EcsClient) {
+		if ecsClient.logger != nil {
+			ecsClient.logger = ecsclientgowrapper.NewLevelLogger(ecsClient.logger, minLevel)
+		}
+	}
+}
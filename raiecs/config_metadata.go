@@ -0,0 +1,94 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ConfigMetadata is the metadata ECS sends along with the config in the "Headers" and "ConfigIDs" properties
+type ConfigMetadata struct {
+	// The ETag of the ECS response
+	ETag string
+
+	// The time the ECS response expires. Zero if ECS did not send a valid Expires header.
+	Expires time.Time
+
+	// The country code of the ECS response. Empty if ECS did not send one.
+	CountryCode string
+
+	// The HTTP status code of the ECS response. Zero if ECS did not send one.
+	StatusCode int
+
+	// The ECS config IDs (e.g. "P-D-1129197-1-172") by project team
+	ConfigIDs map[string]string
+
+	// The overrides applied on top of the option, see WithEnvironmentOverrides and WithOverridesFile. Only set in the metadata passed
+	// to the callbacks of an options monitor, as overrides apply to single options; LastMetadata never has any.
+	Overrides []OptionOverride
+}
+
+// ConfigID returns the ECS config ID the config of projectTeam was served under, or an empty string if there is none
+func (configMetadata ConfigMetadata) ConfigID(projectTeam string) string {
+	return configMetadata.ConfigIDs[projectTeam]
+}
+
+// clone returns a copy of the metadata that does not share the ConfigIDs map and the Overrides
+func (configMetadata ConfigMetadata) clone() ConfigMetadata {
+	if configMetadata.ConfigIDs != nil {
+		configIDs := make(map[string]string, len(configMetadata.ConfigIDs))
+		for projectTeam, configID := range configMetadata.ConfigIDs {
+			configIDs[projectTeam] = configID
+		}
+		configMetadata.ConfigIDs = configIDs
+	}
+
+	if configMetadata.Overrides != nil {
+		configMetadata.Overrides = append([]OptionOverride(nil), configMetadata.Overrides...)
+	}
+
+	return configMetadata
+}
+
+// configEnvelope contains the metadata properties of the ECS config
+type configEnvelope struct {
+	Headers struct {
+		ETag        string  `json:"ETag"`
+		Expires     string  `json:"Expires"`
+		CountryCode *string `json:"CountryCode"`
+		StatusCode  any     `json:"StatusCode"`
+	} `json:"Headers"`
+
+	ConfigIDs map[string]string `json:"ConfigIDs"`
+}
+
+// parseConfigMetadata extracts the ConfigMetadata from the ECS config. Missing or malformed headers are left empty.
+func parseConfigMetadata(config string) (ConfigMetadata, error) {
+	var envelope configEnvelope
+	if err := json.Unmarshal([]byte(config), &envelope); err != nil {
+		return ConfigMetadata{}, err
+	}
+
+	configMetadata := ConfigMetadata{
+		ETag:      envelope.Headers.ETag,
+		ConfigIDs: envelope.ConfigIDs,
+	}
+
+	if expires, err := http.ParseTime(envelope.Headers.Expires); err == nil {
+		configMetadata.Expires = expires
+	}
+
+	if envelope.Headers.CountryCode != nil {
+		configMetadata.CountryCode = *envelope.Headers.CountryCode
+	}
+
+	switch statusCode := envelope.Headers.StatusCode.(type) {
+	case string:
+		configMetadata.StatusCode, _ = strconv.Atoi(statusCode)
+	case float64:
+		configMetadata.StatusCode = int(statusCode)
+	}
+
+	return configMetadata, nil
+}
@@ -0,0 +1,61 @@
+package ecsgoclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the ECS headers and config IDs are parsed from the config
+func TestParseConfigMetadata(t *testing.T) {
+	metadata, err := parseConfigMetadata(validConfigUpdate1)
+	require.NoError(t, err)
+	require.Equal(t, "someEtag", metadata.ETag)
+	require.Equal(t, time.Date(2023, 12, 19, 13, 1, 9, 0, time.UTC), metadata.Expires)
+	require.Equal(t, "", metadata.CountryCode)
+	require.Equal(t, 200, metadata.StatusCode)
+	require.Equal(t, "P-D-1129197-1-172", metadata.ConfigID("TestProjectTeam"))
+	require.Equal(t, "", metadata.ConfigID("OtherProjectTeam"))
+
+	metadata, err = parseConfigMetadata(`{"Headers": {"CountryCode": "US", "StatusCode": 304, "Expires": "invalid"}}`)
+	require.NoError(t, err)
+	require.Equal(t, "US", metadata.CountryCode)
+	require.Equal(t, 304, metadata.StatusCode)
+	require.True(t, metadata.Expires.IsZero())
+
+	_, err = parseConfigMetadata("{")
+	require.Error(t, err)
+}
+
+// Tests that the metadata is passed to the update callbacks and available through LastMetadata
+func TestEcsGoClientConfigMetadataReceived(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+	require.Equal(t, ConfigMetadata{}, ecsClientInstance.LastMetadata())
+
+	var receivedMetadata ConfigMetadata
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithMetadataUpdateEventCallbackFunc(func(metadata ConfigMetadata, optionsUpdateError error) {
+			receivedMetadata = metadata
+		}))
+	require.NoError(t, err)
+	require.Equal(t, "P-D-1129197-1-172", ecsClientInstance.LastMetadata().ConfigID("TestProjectTeam"))
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`
+	{
+		"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue3"}},
+		"Headers": {"ETag": "otherEtag", "StatusCode": "200"},
+		"ConfigIDs": {"TestProjectTeam": "P-D-1129197-1-173"}
+	}`, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, "otherEtag", receivedMetadata.ETag)
+	require.Equal(t, "P-D-1129197-1-173", receivedMetadata.ConfigID("TestProjectTeam"))
+	require.Equal(t, receivedMetadata, ecsClientInstance.LastMetadata())
+}
@@ -0,0 +1,77 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultsProvider can be implemented by an OptionsUpdateReceiver to provide default options. The option received from ECS is
+// deep-merged onto them, so keys that are absent in ECS keep their default value, and if the option or its project team is missing
+// in ECS entirely, the defaults are applied on their own. The typed monitors implement it for WithDefaultValue and WithDefaultJSON.
+type DefaultsProvider interface {
+	// DefaultOptions returns the JSON of the default options, or nil if there are none
+	DefaultOptions() (json.RawMessage, error)
+}
+
+// WithDefaultValue sets the default options of the monitor to value, which is marshaled to JSON, e.g. a struct of the options type.
+// Note that fields without omitempty are marshaled with their zero value and therefore are defaults as well.
+func WithDefaultValue(value any) MonitorOption {
+	return func(options *monitorOptions) {
+		options.defaultOptions = func() (json.RawMessage, error) {
+			return json.Marshal(value)
+		}
+	}
+}
+
+// WithDefaultJSON sets the default options of the monitor to the JSON document defaults, e.g. a file embedded with go:embed
+func WithDefaultJSON(defaults []byte) MonitorOption {
+	return func(options *monitorOptions) {
+		options.defaultOptions = func() (json.RawMessage, error) {
+			return defaults, nil
+		}
+	}
+}
+
+// setDefaults parses the default options of defaultsProvider and sets them on the options monitor
+func (ecsOptionsMonitor *EcsOptionsMonitor) setDefaults(defaultsProvider DefaultsProvider) error {
+	defaults, err := defaultsProvider.DefaultOptions()
+	if err == nil && defaults == nil {
+		return nil
+	}
+
+	if err == nil {
+		err = json.Unmarshal(defaults, &ecsOptionsMonitor.defaults)
+	}
+
+	if err != nil {
+		return newEcsError(ErrInvalidConfig, ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName, fmt.Errorf("invalid default options: %w", err))
+	}
+
+	ecsOptionsMonitor.hasDefaults = true
+	return nil
+}
+
+// mergeJSONValues deep-merges the parsed JSON value overlay onto base: objects are merged key by key, any other value of overlay,
+// including arrays and null, replaces the one of base. Neither base nor overlay are modified.
+func mergeJSONValues(base any, overlay any) any {
+	baseObject, baseIsObject := base.(map[string]any)
+	overlayObject, overlayIsObject := overlay.(map[string]any)
+	if !baseIsObject || !overlayIsObject {
+		return overlay
+	}
+
+	merged := make(map[string]any, len(baseObject)+len(overlayObject))
+	for key, value := range baseObject {
+		merged[key] = value
+	}
+
+	for key, value := range overlayObject {
+		if baseValue, ok := baseObject[key]; ok {
+			merged[key] = mergeJSONValues(baseValue, value)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
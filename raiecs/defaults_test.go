@@ -0,0 +1,57 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that objects are merged key by key and all other values of the overlay replace the base
+func TestMergeJSONValues(t *testing.T) {
+	var base, overlay any
+	require.NoError(t, json.Unmarshal([]byte(`{"a": 1, "b": {"c": 2, "d": [1, 2]}, "e": {"f": 3}}`), &base))
+	require.NoError(t, json.Unmarshal([]byte(`{"b": {"d": [3]}, "e": null, "g": "new"}`), &overlay))
+
+	merged, err := json.Marshal(mergeJSONValues(base, overlay))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a": 1, "b": {"c": 2, "d": [3]}, "e": null, "g": "new"}`, string(merged))
+
+	unchangedBase, err := json.Marshal(base)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a": 1, "b": {"c": 2, "d": [1, 2]}, "e": {"f": 3}}`, string(unchangedBase))
+}
+
+// Tests that the default options fill in the keys that are absent in ECS and are used on their own if the option is missing
+func TestTypedMonitorDefaults(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue1"}}}`, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithDefaultValue(TypedTestConfig{TestProperty: "DefaultValue", TestIntegerWithMaxValue100: 42}))
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "TestValue1", TestIntegerWithMaxValue100: 42}, monitor.Get())
+
+	missingOptionMonitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "MissingConfigName",
+		WithDefaultJSON([]byte(`{"TestProperty": "DefaultValue"}`)))
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "DefaultValue"}, missingOptionMonitor.Get())
+
+	missingTeamMonitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "MissingProjectTeam", "ConfigName",
+		WithDefaultJSON([]byte(`{"TestProperty": "DefaultValue"}`)))
+	require.NoError(t, err)
+	require.Equal(t, "DefaultValue", missingTeamMonitor.Get().TestProperty)
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestIntegerWithMaxValue100": 7}, "MissingConfigName": {"TestIntegerWithMaxValue100": 8}}}`, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	require.Equal(t, TypedTestConfig{TestProperty: "DefaultValue", TestIntegerWithMaxValue100: 7}, monitor.Get())
+	require.Equal(t, TypedTestConfig{TestProperty: "DefaultValue", TestIntegerWithMaxValue100: 8}, missingOptionMonitor.Get())
+
+	_, err = AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName", WithDefaultJSON([]byte(`{`)))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
@@ -0,0 +1,308 @@
+package ecsgoclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// DefaultCallbackBufferSize is the number of update events that are queued per update callback if no buffer size is provided
+const DefaultCallbackBufferSize = 16
+
+// OverflowPolicy decides which update events are dropped if the queue of an update callback is full
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest drops the oldest queued update event to make room for the new one
+	OverflowDropOldest OverflowPolicy = iota
+
+	// OverflowCoalesceLatest replaces all queued update events with the new one. If the new update succeeded, its Previous value and
+	// changed paths are adjusted, so the callback still receives the complete change since the last update event it has seen.
+	OverflowCoalesceLatest
+)
+
+// SubscriptionOption configures how the update events are dispatched to an update callback
+type SubscriptionOption func(*subscriptionOptions)
+
+// subscriptionOptions are the settings that can be provided through SubscriptionOption
+type subscriptionOptions struct {
+	bufferSize     int
+	overflowPolicy OverflowPolicy
+}
+
+// WithBufferSize sets the number of update events that are queued for the update callback before the overflow policy applies
+func WithBufferSize(bufferSize int) SubscriptionOption {
+	return func(options *subscriptionOptions) {
+		options.bufferSize = bufferSize
+	}
+}
+
+// WithOverflowPolicy sets which update events are dropped if the queue of the update callback is full
+func WithOverflowPolicy(overflowPolicy OverflowPolicy) SubscriptionOption {
+	return func(options *subscriptionOptions) {
+		options.overflowPolicy = overflowPolicy
+	}
+}
+
+// dispatcher is implemented by all subscriptions, so the EcsClient can shut them down regardless of their event type
+type dispatcher interface {
+	close()
+	waitIdle()
+	stopped() <-chan struct{}
+	isDispatching() bool
+}
+
+// eventSubscription is a callback registered on the EcsClient. The events are queued and delivered in order on a goroutine of the
+// subscription, so a slow or re-entrant callback blocks neither the config updates nor the other callbacks.
+type eventSubscription[E any] struct {
+	// name describes the callback in log messages
+	name     string
+	callback func(event E)
+	options  subscriptionOptions
+	logger   ecsclientgowrapper.Logger
+
+	// tracer creates a dispatch span with spanAttributes for every delivered event
+	tracer         trace.Tracer
+	spanAttributes []attribute.KeyValue
+
+	// coalesce merges the latest event into the oldest queued event for OverflowCoalesceLatest, may be nil to keep only the latest event
+	coalesce func(oldest E, latest E) E
+
+	// onPanic is called with the event the callback panicked on, may be nil
+	onPanic func(event E, panicError *PanicError)
+
+	// mutex guards queue, dispatching and closed. idle is signaled whenever the queue has been drained.
+	mutex       sync.Mutex
+	idle        *sync.Cond
+	queue       []queuedEvent[E]
+	dispatching bool
+	closed      bool
+
+	wakeup       chan struct{}
+	done         chan struct{}
+	unsubscribed atomic.Bool
+}
+
+// queuedEvent is an event waiting for delivery together with the span context of the update it belongs to
+type queuedEvent[E any] struct {
+	event       E
+	spanContext trace.SpanContext
+}
+
+// newEventSubscription creates a subscription for callback and starts its dispatch goroutine
+func newEventSubscription[E any](name string, callback func(E), logger ecsclientgowrapper.Logger, tracer trace.Tracer, spanAttributes []attribute.KeyValue, coalesce func(E, E) E, onPanic func(E, *PanicError), opts ...SubscriptionOption) *eventSubscription[E] {
+	options := subscriptionOptions{bufferSize: DefaultCallbackBufferSize, overflowPolicy: OverflowDropOldest}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.bufferSize <= 0 {
+		options.bufferSize = DefaultCallbackBufferSize
+	}
+
+	subscription := &eventSubscription[E]{
+		name:           name,
+		callback:       callback,
+		options:        options,
+		logger:         logger,
+		tracer:         tracer,
+		spanAttributes: spanAttributes,
+		coalesce:       coalesce,
+		onPanic:        onPanic,
+		wakeup:         make(chan struct{}, 1),
+		done:           make(chan struct{}),
+	}
+	subscription.idle = sync.NewCond(&subscription.mutex)
+
+	go subscription.run()
+	return subscription
+}
+
+// newUpdateEventSubscription creates a subscription for an update callback of the options monitor of optionName of projectTeam
+func newUpdateEventSubscription(projectTeam string, optionName string, callback EcsOptionsUpdateEventCallbackFunc, logger ecsclientgowrapper.Logger, tracer trace.Tracer, onPanic func(OptionsUpdateEvent, *PanicError), opts ...SubscriptionOption) *eventSubscription[OptionsUpdateEvent] {
+	name := fmt.Sprintf("update callback of option '%v' of project team '%v'", optionName, projectTeam)
+	return newEventSubscription(name, callback, logger, tracer, optionAttributes(projectTeam, optionName), coalesceUpdateEvents, onPanic, opts...)
+}
+
+// enqueue queues the event for delivery and applies the overflow policy if the queue is full.
+// The dispatch span of the event becomes a child of the span in ctx.
+func (subscription *eventSubscription[E]) enqueue(ctx context.Context, event E) {
+	subscription.mutex.Lock()
+	if subscription.closed {
+		subscription.mutex.Unlock()
+		return
+	}
+
+	overflowed := len(subscription.queue) >= subscription.options.bufferSize
+	if overflowed {
+		switch subscription.options.overflowPolicy {
+		case OverflowCoalesceLatest:
+			if subscription.coalesce != nil {
+				event = subscription.coalesce(subscription.queue[0].event, event)
+			}
+			subscription.queue = subscription.queue[:0]
+		default:
+			subscription.queue = subscription.queue[1:]
+		}
+	}
+
+	subscription.queue = append(subscription.queue, queuedEvent[E]{event: event, spanContext: trace.SpanContextFromContext(ctx)})
+	subscription.mutex.Unlock()
+
+	subscription.signal()
+
+	if overflowed {
+		subscription.logger.Log(ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, fmt.Sprintf("%v can't keep up, dropped queued events", subscription.name))
+	}
+}
+
+// close stops the subscription after the queued update events have been delivered
+func (subscription *eventSubscription[E]) close() {
+	subscription.mutex.Lock()
+	subscription.closed = true
+	subscription.mutex.Unlock()
+
+	subscription.signal()
+}
+
+// unsubscribe stops the subscription and drops the queued update events. It can be called from within the callback.
+func (subscription *eventSubscription[E]) unsubscribe() {
+	subscription.unsubscribed.Store(true)
+
+	subscription.mutex.Lock()
+	subscription.queue = nil
+	subscription.closed = true
+	subscription.mutex.Unlock()
+
+	subscription.signal()
+}
+
+// waitIdle blocks until all queued update events have been delivered
+func (subscription *eventSubscription[E]) waitIdle() {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	for len(subscription.queue) > 0 || subscription.dispatching {
+		subscription.idle.Wait()
+	}
+}
+
+// stopped returns a channel that is closed once the dispatch goroutine has stopped
+func (subscription *eventSubscription[E]) stopped() <-chan struct{} {
+	return subscription.done
+}
+
+// isDispatching returns whether the callback is running or about to be called
+func (subscription *eventSubscription[E]) isDispatching() bool {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	return subscription.dispatching
+}
+
+// signal wakes up the dispatch goroutine
+func (subscription *eventSubscription[E]) signal() {
+	select {
+	case subscription.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the queued update events until the subscription is closed and its queue is drained
+func (subscription *eventSubscription[E]) run() {
+	defer close(subscription.done)
+
+	for {
+		subscription.mutex.Lock()
+		for len(subscription.queue) == 0 {
+			subscription.dispatching = false
+			subscription.idle.Broadcast()
+
+			if subscription.closed {
+				subscription.mutex.Unlock()
+				return
+			}
+
+			subscription.mutex.Unlock()
+			<-subscription.wakeup
+			subscription.mutex.Lock()
+		}
+
+		queued := subscription.queue[0]
+		subscription.queue = subscription.queue[1:]
+		subscription.dispatching = true
+		subscription.mutex.Unlock()
+
+		subscription.deliver(queued)
+	}
+}
+
+// deliver calls the callback with the event unless the subscription has been unsubscribed. A panic of the callback is logged,
+// recorded on the dispatch span and reported to onPanic.
+func (subscription *eventSubscription[E]) deliver(queued queuedEvent[E]) {
+	if subscription.unsubscribed.Load() {
+		return
+	}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), queued.spanContext)
+	_, span := subscription.tracer.Start(ctx, SpanNameDispatch, trace.WithAttributes(subscription.spanAttributes...))
+
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			span.End()
+			return
+		}
+
+		panicError := newPanicError(recovered)
+		endSpan(span, panicError)
+		ecsclientgowrapper.LogAttrs(subscription.logger, ecsclientgowrapper.ECS_LOG_LEVEL_ERROR, fmt.Sprintf("%v panicked", subscription.name), errorLogAttrs(panicError)...)
+
+		if subscription.onPanic != nil {
+			subscription.onPanic(queued.event, panicError)
+		}
+	}()
+
+	subscription.callback(queued.event)
+}
+
+// removeSubscription stops subscription and removes it from the subscriptions guarded by mutex. The slice is replaced instead of
+// modified, as it is iterated without holding mutex. It is safe to call multiple times.
+func removeSubscription[E any](mutex *sync.Mutex, subscriptions *[]*eventSubscription[E], subscription *eventSubscription[E]) {
+	if subscription.unsubscribed.Load() {
+		return
+	}
+	subscription.unsubscribe()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	remaining := make([]*eventSubscription[E], 0, len(*subscriptions))
+	for _, registeredSubscription := range *subscriptions {
+		if registeredSubscription != subscription {
+			remaining = append(remaining, registeredSubscription)
+		}
+	}
+	*subscriptions = remaining
+}
+
+// coalesceUpdateEvents merges latest with the queued update events starting at oldest. A successful latest update event is changed
+// to describe the change since the last delivered update event, a failed one is kept as it is.
+func coalesceUpdateEvents(oldest OptionsUpdateEvent, latest OptionsUpdateEvent) OptionsUpdateEvent {
+	if latest.Err != nil || latest.Current == nil {
+		return latest
+	}
+
+	latest.Previous = oldest.Previous
+	if changedPaths, err := jsonChangedPaths(latest.Previous, latest.Current); err == nil {
+		latest.ChangedPaths = changedPaths
+	}
+
+	return latest
+}
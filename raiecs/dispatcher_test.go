@@ -0,0 +1,148 @@
+package ecsgoclient
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that a blocked callback neither blocks the config updates nor the other callbacks, and that callbacks can call back into the client
+func TestDispatcherSlowAndReentrantCallbacks(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	releaseCallback := make(chan struct{})
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		<-releaseCallback
+	})
+	require.NoError(t, err)
+
+	reentrantCallbackDone := make(chan error, 1)
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		_, registerErr := ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(error) {})
+		reentrantCallbackDone <- registerErr
+	})
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	require.Equal(t, "TestValue2", testConfig.TestProperty)
+
+	select {
+	case registerErr := <-reentrantCallbackDone:
+		require.NoError(t, registerErr)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the re-entrant callback was not called")
+	}
+
+	close(releaseCallback)
+	waitForDispatch(ecsClientInstance)
+}
+
+// Tests that a full queue drops the oldest update event with OverflowDropOldest
+func TestDispatcherOverflowDropOldest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var received []string
+	subscription := newUpdateEventSubscription("TestProjectTeam", "ConfigName", func(event OptionsUpdateEvent) {
+		if event.CheckSum == "blocking" {
+			close(started)
+			<-release
+			return
+		}
+		received = append(received, event.CheckSum)
+	}, &NoopLogger{}, noopTracer(), nil, WithBufferSize(2), WithOverflowPolicy(OverflowDropOldest))
+
+	subscription.enqueue(context.Background(), OptionsUpdateEvent{CheckSum: "blocking"})
+	<-started
+
+	for _, checkSum := range []string{"1", "2", "3", "4"} {
+		subscription.enqueue(context.Background(), OptionsUpdateEvent{CheckSum: checkSum})
+	}
+
+	close(release)
+	subscription.waitIdle()
+	require.Equal(t, []string{"3", "4"}, received)
+}
+
+// Tests that a full queue is coalesced into the latest update event with OverflowCoalesceLatest
+func TestDispatcherOverflowCoalesceLatest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var received []OptionsUpdateEvent
+	subscription := newUpdateEventSubscription("TestProjectTeam", "ConfigName", func(event OptionsUpdateEvent) {
+		if event.CheckSum == "blocking" {
+			close(started)
+			<-release
+			return
+		}
+		received = append(received, event)
+	}, &NoopLogger{}, noopTracer(), nil, WithBufferSize(1), WithOverflowPolicy(OverflowCoalesceLatest))
+
+	subscription.enqueue(context.Background(), OptionsUpdateEvent{CheckSum: "blocking"})
+	<-started
+
+	subscription.enqueue(context.Background(), OptionsUpdateEvent{Previous: json.RawMessage(`{"a": 1, "b": 1}`), Current: json.RawMessage(`{"a": 2, "b": 1}`), CheckSum: "2"})
+	subscription.enqueue(context.Background(), OptionsUpdateEvent{Previous: json.RawMessage(`{"a": 2, "b": 1}`), Current: json.RawMessage(`{"a": 2, "b": 3}`), CheckSum: "3"})
+
+	close(release)
+	subscription.waitIdle()
+	require.Len(t, received, 1)
+	require.Equal(t, "3", received[0].CheckSum)
+	require.JSONEq(t, `{"a": 1, "b": 1}`, string(received[0].Previous))
+	require.Equal(t, []string{"/a", "/b"}, received[0].ChangedPaths)
+}
+
+// Tests that a panicking callback does not stop the delivery of later update events
+func TestDispatcherCallbackPanic(t *testing.T) {
+	var received []string
+	subscription := newUpdateEventSubscription("TestProjectTeam", "ConfigName", func(event OptionsUpdateEvent) {
+		if event.CheckSum == "panic" {
+			panic("callback failed")
+		}
+		received = append(received, event.CheckSum)
+	}, &NoopLogger{}, noopTracer(), nil)
+
+	subscription.enqueue(context.Background(), OptionsUpdateEvent{CheckSum: "panic"})
+	subscription.enqueue(context.Background(), OptionsUpdateEvent{CheckSum: "1"})
+
+	subscription.waitIdle()
+	require.Equal(t, []string{"1"}, received)
+}
+
+// Tests that Close delivers the queued update events before it returns
+func TestDispatcherCloseDeliversQueuedEvents(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	configUpdateCounter := 0
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithUpdateEventCallbackFunc(func(innerOptionsUpdateError error) {
+			time.Sleep(10 * time.Millisecond)
+			configUpdateCounter++
+		}))
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	ecsClientInstance.TriggerAllUpdateEventCallbacks()
+
+	require.NoError(t, ecsClientInstance.Close(context.Background()))
+	require.Equal(t, 2, configUpdateCounter)
+}
@@ -0,0 +1,85 @@
+package ecsgoclient
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// EcsEvent is an event the ecs C library reported for the client
+type EcsEvent struct {
+	// The type of the event
+	Type ecsclientgowrapper.ECS_EVENT_TYPE
+
+	// The message of the ecs C library, e.g. the reason of an ECS_EVENT_CONFIGURATION_ERROR
+	Message string
+}
+
+// EcsEventCallbackFunc is a callback func the user can register to receive the events of the ecs C library
+type EcsEventCallbackFunc func(event EcsEvent)
+
+// OnEvent registers a callback function that receives every event of the ecs C library. The callback is called asynchronously,
+// in the order of the events. The returned UnsubscribeFunc removes the callback again.
+func (ecsClient *EcsClient) OnEvent(eventCallback EcsEventCallbackFunc, opts ...SubscriptionOption) (UnsubscribeFunc, error) {
+	if !ecsClient.beginUpdate() {
+		return nil, ErrClientClosed
+	}
+	defer ecsClient.endUpdate()
+
+	subscription := newEventSubscription[EcsEvent]("ecs event callback", eventCallback, ecsClient.logger, ecsClient.tracer, nil, nil, nil, opts...)
+
+	ecsClient.eventCallbacksMutex.Lock()
+	ecsClient.eventCallbacks = append(ecsClient.eventCallbacks, subscription)
+	ecsClient.eventCallbacksMutex.Unlock()
+
+	return func() {
+		removeSubscription(&ecsClient.eventCallbacksMutex, &ecsClient.eventCallbacks, subscription)
+	}, nil
+}
+
+// eventSubscriptions returns the registered event callbacks. The returned slice must not be modified.
+func (ecsClient *EcsClient) eventSubscriptions() []*eventSubscription[EcsEvent] {
+	ecsClient.eventCallbacksMutex.Lock()
+	defer ecsClient.eventCallbacksMutex.Unlock()
+
+	return ecsClient.eventCallbacks
+}
+
+// handleEvent forwards an event of the ecs C library to the event callbacks and updates the options monitors, unless the client is closed.
+// Config changes are fetched and applied, errors are reported to the update callbacks without fetching the config.
+func (ecsClient *EcsClient) handleEvent(event EcsEvent) {
+	if !ecsClient.beginUpdate() {
+		return
+	}
+	defer ecsClient.endUpdate()
+
+	ecsClient.metrics.ObserveEvent(event.Type)
+	for _, subscription := range ecsClient.eventSubscriptions() {
+		subscription.enqueue(context.Background(), event)
+	}
+
+	if event.Type != ecsclientgowrapper.ECS_EVENT_CONFIGURATION_ERROR {
+		ecsClient.updateOptions(context.Background(), false, event.Type)
+		return
+	}
+
+	ctx, span := ecsClient.tracer.Start(context.Background(), SpanNameUpdate, trace.WithAttributes(eventTypeAttribute(event.Type), AttributeInitial.Bool(false)))
+
+	err := newEcsError(ErrFetchFailed, "", "", fmt.Errorf("ecs configuration error event: %v", event.Message))
+	defer endSpan(span, err)
+	ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_ERROR, "updating config failed", errorLogAttrs(err)...)
+
+	metadata := ecsClient.LastMetadata()
+
+	ecsClient.callbackFuncsMutex.RLock()
+	defer ecsClient.callbackFuncsMutex.RUnlock()
+	for _, listener := range ecsClient.ecsOptionMonitors {
+		listener.recordUpdate(ecsClient.now(), err)
+		updateEvent := listener.newUpdateEvent(metadata, err)
+		updateEvent.EventType = event.Type
+		listener.notify(ctx, updateEvent)
+	}
+}
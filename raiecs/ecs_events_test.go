@@ -0,0 +1,60 @@
+package ecsgoclient
+
+import (
+	"testing"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the events of the ecs C library are forwarded to the event callbacks and error events don't trigger a fetch
+func TestEcsGoClientOnEvent(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	var updateEvents []OptionsUpdateEvent
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithOptionsUpdateEventCallbackFunc(func(event OptionsUpdateEvent) {
+			updateEvents = append(updateEvents, event)
+		}))
+	require.NoError(t, err)
+
+	var events []EcsEvent
+	unsubscribe, err := ecsClientInstance.OnEvent(func(event EcsEvent) {
+		events = append(events, event)
+	})
+	require.NoError(t, err)
+
+	ecsClientInstance.handleEvent(EcsEvent{Type: ecsclientgowrapper.ECS_EVENT_CONFIGURATION_ERROR, Message: "request timed out"})
+	waitForDispatch(ecsClientInstance)
+	ecsConfigGetter.AssertNumberOfCalls(t, "GetConfig", 1)
+	require.Len(t, updateEvents, 1)
+	require.Equal(t, ecsclientgowrapper.ECS_EVENT_CONFIGURATION_ERROR, updateEvents[0].EventType)
+	require.ErrorIs(t, updateEvents[0].Err, ErrFetchFailed)
+	require.ErrorContains(t, updateEvents[0].Err, "request timed out")
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.handleEvent(EcsEvent{Type: ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE})
+	waitForDispatch(ecsClientInstance)
+	ecsConfigGetter.AssertNumberOfCalls(t, "GetConfig", 2)
+	require.Len(t, updateEvents, 2)
+	require.NoError(t, updateEvents[1].Err)
+	require.True(t, updateEvents[1].FromCache)
+
+	require.Equal(t, []EcsEvent{
+		{Type: ecsclientgowrapper.ECS_EVENT_CONFIGURATION_ERROR, Message: "request timed out"},
+		{Type: ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE},
+	}, events)
+
+	unsubscribe()
+	ecsClientInstance.handleEvent(EcsEvent{Type: ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED})
+	waitForDispatch(ecsClientInstance)
+	require.Len(t, events, 2)
+	require.Len(t, updateEvents, 2)
+}
@@ -2,12 +2,21 @@ package ecsgoclient
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/raiecs/ecsclientgowrapper"
 )
@@ -15,13 +24,208 @@ import (
 // EcsUpdateEventCallbackFunc is a callback func the user can register and will be called if a config update has been received
 type EcsUpdateEventCallbackFunc func(optionsUpdateError error)
 
+// EcsMetadataUpdateEventCallbackFunc is like EcsUpdateEventCallbackFunc, but also receives the metadata of the ECS config the update was received with
+type EcsMetadataUpdateEventCallbackFunc func(metadata ConfigMetadata, optionsUpdateError error)
+
+// EcsOptionsUpdateEventCallbackFunc is a callback func that receives the details of each config update of an options monitor
+type EcsOptionsUpdateEventCallbackFunc func(event OptionsUpdateEvent)
+
+// OptionsUpdateEvent describes a config update of an options monitor
+type OptionsUpdateEvent struct {
+	// The project team of the options monitor
+	ProjectTeam string
+
+	// The option name of the options monitor
+	OptionName string
+
+	// The ECS event that triggered the update
+	EventType ecsclientgowrapper.ECS_EVENT_TYPE
+
+	// The raw JSON of the option before the update, nil if no config had been accepted before
+	Previous json.RawMessage
+
+	// The raw JSON of the accepted option, nil if the update failed
+	Current json.RawMessage
+
+	// The sha256 checksum of Current
+	CheckSum string
+
+	// The JSON pointers of the values that differ between Previous and Current. Contains only the root pointer "" if there was no previous config.
+	ChangedPaths []string
+
+	// The metadata of the ECS config the update was received with
+	Metadata ConfigMetadata
+
+	// The overrides applied on top of the option received from ECS, see WithEnvironmentOverrides and WithOverridesFile.
+	// Metadata.Overrides contains the same overrides.
+	Overrides []OptionOverride
+
+	// Whether the ecs C library served the config from its cache instead of fetching it from ECS
+	FromCache bool
+
+	// The error if the update failed
+	Err error
+}
+
+// UnsubscribeFunc removes an options monitor or update callback from the client again. It is safe to call multiple times.
+type UnsubscribeFunc func()
+
 // ecsOptionsUpdateFunc is an internal type to auto update options that are registered on the client
-type ecsOptionsUpdateFunc func(config string, logger ecsclientgowrapper.Logger) (error, bool)
+type ecsOptionsUpdateFunc func(config string, metadata ConfigMetadata, logger ecsclientgowrapper.Logger) (OptionsUpdateEvent, bool)
+
+// ecsOptionApplyFunc applies the defaults and overrides to the received option and updates the options with it.
+// A nil receivedOption applies the last received option again.
+type ecsOptionApplyFunc func(received *receivedOption) (OptionsUpdateEvent, bool)
+
+// receivedOption is the option of an options monitor as received from ECS, before the defaults and overrides have been applied
+type receivedOption struct {
+	value    any
+	found    bool
+	metadata ConfigMetadata
+
+	// stale is true if the option has been loaded from the last-known-good snapshot instead of ECS
+	stale bool
+}
 
 // EcsOptionsMonitor contains the info for the options monitor (the func to do the update of TOptions, and the registered callbacks if the TOptions update was invoked)
 type EcsOptionsMonitor struct {
-	optionsUpdateFunc  ecsOptionsUpdateFunc
-	configUpdateEvents []EcsUpdateEventCallbackFunc
+	projectTeam       string
+	optionName        string
+	optionsUpdateFunc ecsOptionsUpdateFunc
+	applyOptionFunc   ecsOptionApplyFunc
+
+	// options receives the last-known-good snapshot if the initial config can't be fetched
+	options OptionsUpdateReceiver
+
+	// defaults are the parsed default options the option from ECS is merged onto, only set if hasDefaults is true
+	defaults    interface{}
+	hasDefaults bool
+
+	// schema validates the option before it is passed to the OptionsUpdateReceiver, nil if there is no schema
+	schema *jsonschema.Schema
+
+	// redactor masks the secrets of the option in the log messages and update events, nil if nothing is redacted
+	redactor *redactor
+
+	// callbacksMutex guards configUpdateEvents, which is replaced instead of modified when a callback is removed
+	callbacksMutex     sync.Mutex
+	configUpdateEvents []*eventSubscription[OptionsUpdateEvent]
+
+	// updateMutex serializes the updates of the options, as the native event callback can fire from any goroutine.
+	// It also guards lastReceived, the last option received from ECS, which is applied again if the overrides file changes.
+	updateMutex  sync.Mutex
+	snapshot     atomic.Pointer[OptionsSnapshot]
+	lastReceived *receivedOption
+
+	// healthMutex guards health
+	healthMutex sync.Mutex
+	health      MonitorHealth
+}
+
+// OptionsSnapshot is an immutable snapshot of an accepted options config. It must not be modified.
+type OptionsSnapshot struct {
+	// The raw JSON of the option as passed to the OptionsUpdateReceiver, after the defaults and overrides have been applied
+	Value json.RawMessage
+
+	// The sha256 checksum of Value
+	CheckSum string
+
+	// The ECS ConfigID of the project team the option was received with, empty if ECS didn't report one
+	ConfigID string
+
+	// Stale is true if the option has been loaded from the snapshot directory because the initial config couldn't be fetched,
+	// see WithSnapshotDirectory
+	Stale bool
+}
+
+// Current returns the snapshot of the last accepted config, or nil if no config has been accepted yet
+func (ecsOptionsMonitor *EcsOptionsMonitor) Current() *OptionsSnapshot {
+	return ecsOptionsMonitor.snapshot.Load()
+}
+
+// hasReceivedOption returns whether the options monitor has received an option from ECS or loaded one from the last-known-good snapshot
+func (ecsOptionsMonitor *EcsOptionsMonitor) hasReceivedOption() bool {
+	ecsOptionsMonitor.updateMutex.Lock()
+	defer ecsOptionsMonitor.updateMutex.Unlock()
+
+	return ecsOptionsMonitor.lastReceived != nil
+}
+
+// newUpdateEvent creates an OptionsUpdateEvent for the options monitor based on the last accepted config, without any change
+func (ecsOptionsMonitor *EcsOptionsMonitor) newUpdateEvent(metadata ConfigMetadata, err error) OptionsUpdateEvent {
+	event := OptionsUpdateEvent{
+		ProjectTeam: ecsOptionsMonitor.projectTeam,
+		OptionName:  ecsOptionsMonitor.optionName,
+		EventType:   ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED,
+		Metadata:    metadata,
+		Err:         err,
+	}
+
+	if current := ecsOptionsMonitor.snapshot.Load(); current != nil {
+		event.Previous = current.Value
+		event.CheckSum = current.CheckSum
+	}
+
+	return event
+}
+
+// notify queues the event for all registered callbacks. Their dispatch spans become children of the span in ctx.
+func (ecsOptionsMonitor *EcsOptionsMonitor) notify(ctx context.Context, event OptionsUpdateEvent) {
+	for _, subscription := range ecsOptionsMonitor.subscriptions() {
+		event.Metadata = event.Metadata.clone()
+		subscription.enqueue(ctx, event)
+	}
+}
+
+// reportPanic notifies the registered callbacks that a callback panicked on event, unless it panicked on the report of another panic
+func (ecsOptionsMonitor *EcsOptionsMonitor) reportPanic(event OptionsUpdateEvent, panicError *PanicError) {
+	if errors.Is(event.Err, ErrPanicked) {
+		return
+	}
+
+	panicEvent := ecsOptionsMonitor.newUpdateEvent(event.Metadata, newEcsError(ErrPanicked, ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName, panicError))
+	panicEvent.EventType = event.EventType
+	ecsOptionsMonitor.notify(context.Background(), panicEvent)
+}
+
+// subscriptions returns the registered callbacks. The returned slice must not be modified.
+func (ecsOptionsMonitor *EcsOptionsMonitor) subscriptions() []*eventSubscription[OptionsUpdateEvent] {
+	ecsOptionsMonitor.callbacksMutex.Lock()
+	defer ecsOptionsMonitor.callbacksMutex.Unlock()
+
+	return ecsOptionsMonitor.configUpdateEvents
+}
+
+// subscribe registers configUpdateEvent on the options monitor and returns the func to remove it again
+func (ecsOptionsMonitor *EcsOptionsMonitor) subscribe(configUpdateEvent EcsOptionsUpdateEventCallbackFunc, logger ecsclientgowrapper.Logger, tracer trace.Tracer, opts ...SubscriptionOption) UnsubscribeFunc {
+	// the events are queued unredacted, so coalescing still detects changes of secrets, and are redacted just before the delivery
+	callback := configUpdateEvent
+	if ecsOptionsMonitor.redactor != nil {
+		callback = func(event OptionsUpdateEvent) {
+			configUpdateEvent(ecsOptionsMonitor.redactor.redactEvent(event))
+		}
+	}
+
+	subscription := newUpdateEventSubscription(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName, callback, logger, tracer, ecsOptionsMonitor.reportPanic, opts...)
+
+	ecsOptionsMonitor.callbacksMutex.Lock()
+	ecsOptionsMonitor.configUpdateEvents = append(ecsOptionsMonitor.configUpdateEvents, subscription)
+	ecsOptionsMonitor.callbacksMutex.Unlock()
+
+	return func() {
+		removeSubscription(&ecsOptionsMonitor.callbacksMutex, &ecsOptionsMonitor.configUpdateEvents, subscription)
+	}
+}
+
+// unsubscribeAll removes all callbacks from the options monitor
+func (ecsOptionsMonitor *EcsOptionsMonitor) unsubscribeAll() {
+	ecsOptionsMonitor.callbacksMutex.Lock()
+	defer ecsOptionsMonitor.callbacksMutex.Unlock()
+
+	for _, subscription := range ecsOptionsMonitor.configUpdateEvents {
+		subscription.unsubscribe()
+	}
+	ecsOptionsMonitor.configUpdateEvents = nil
 }
 
 // EcsConfigGetter is the interface that is internally used for fetching the config from ECS
@@ -29,6 +233,12 @@ type EcsConfigGetter interface {
 	GetConfig(ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error)
 }
 
+// ContextConfigGetter can be implemented by an EcsConfigGetter that supports cancelling a config fetch through a context.
+// EcsConfigGetters that don't implement it are called in the background and abandoned when the context is done.
+type ContextConfigGetter interface {
+	GetConfigContext(ctx context.Context, ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error)
+}
+
 // EcsClientOptions are the ECS configuration options that the ECS-GO-Client exposes
 type EcsClientOptions struct {
 	// The ECS client name
@@ -37,10 +247,14 @@ type EcsClientOptions struct {
 	// The ECS project team names
 	ProjectTeams []string
 
+	// The ECS environment to fetch the config from. If nil defaults to ECS_ENVIRONMENT_TYPE_PRODUCTION.
+	Environment *ecsclientgowrapper.ECS_ENVIRONMENT_TYPE
+
 	// The target filters, typically service level context (e.g. environment, region, etc.).
 	TargetFilters map[string][]string
 
-	// The Logger
+	// The Logger. The ecs C library doesn't tell which client a log message belongs to, so the log messages of the ecs C library
+	// are passed to the loggers of all clients in the process.
 	Logger ecsclientgowrapper.Logger
 
 	// Path to default configurations.
@@ -67,18 +281,59 @@ type EcsClientOptions struct {
 	// The method to be used as authentication for ECS Config Service requests.
 	AuthenticationMethod ecsclientgowrapper.ECS_AUTHENTICATION_METHOD
 
-	// The min loglevel
+	// The min loglevel, applied to the log messages of the ecs C library and of the Go client. The log messages of the Go client are
+	// not filtered if it is not set (ECS_LOG_LEVEL_NONE), use WithLogLevel to silence them.
 	LogLevel ecsclientgowrapper.ECS_LOG_LEVEL
 
 	// Enable A&E ExP Control Tower based flighting for Cerberus.
 	EnableExp int
 }
 
+// nativeClientDestroyer is implemented by EcsConfigGetters that hold native resources, like the ecs C library wrapper
+type nativeClientDestroyer interface {
+	DestroyClient() error
+}
+
 type EcsClient struct {
 	internalEcsClient  EcsConfigGetter
 	logger             ecsclientgowrapper.Logger
 	ecsOptionMonitors  map[any]*EcsOptionsMonitor
 	callbackFuncsMutex sync.RWMutex
+
+	// lifecycleMutex guards closed and the start of in-flight updates
+	lifecycleMutex  sync.Mutex
+	closed          bool
+	inFlightUpdates sync.WaitGroup
+	destroyOnce     sync.Once
+	destroyErr      error
+
+	lastMetadata atomic.Pointer[ConfigMetadata]
+
+	// eventCallbacksMutex guards eventCallbacks, which is replaced instead of modified when a callback is removed
+	eventCallbacksMutex sync.Mutex
+	eventCallbacks      []*eventSubscription[EcsEvent]
+
+	// now returns the current time for the health tracking, replaced in tests
+	now func() time.Time
+
+	metrics MetricsRecorder
+	tracer  trace.Tracer
+
+	// redactedPaths are masked in the log messages and update events of every option, see WithRedactedPaths
+	redactedPaths       []string
+	checksumOnlyLogging bool
+
+	// snapshotDirectory stores the last-known-good options, empty if they are not persisted
+	snapshotDirectory string
+
+	// environmentOverrides and overridesFile are the sources of the overrides of the operators, environ is replaced in tests
+	environmentOverrides bool
+	overridesFile        string
+	environ              func() []string
+
+	// overridesPollInterval is the interval the overridesFile is polled in, overridesWatchStop stops polling it once the client is closed
+	overridesPollInterval time.Duration
+	overridesWatchStop    chan struct{}
 }
 
 type OptionsUpdateReceiver interface {
@@ -92,9 +347,50 @@ const (
 )
 
 // NewEcsClient creates a new ecs client which calls into the ecs C library to fetch the config
-func NewEcsClient(ecsClientOptions EcsClientOptions) (*EcsClient, error) {
+func NewEcsClient(ecsClientOptions EcsClientOptions, opts ...ClientOption) (*EcsClient, error) {
 	var callbackFunction ecsclientgowrapper.EcsConfigurationEventCallbackFunc = func(event ecsclientgowrapper.ECS_EVENT_TYPE, message string) {}
 
+	// the ecs C library only filters its own log messages, so the messages of the Go side are filtered by the same LogLevel, if it is set
+	if ecsClientOptions.Logger != nil && ecsClientOptions.LogLevel != ecsclientgowrapper.ECS_LOG_LEVEL_NONE {
+		ecsClientOptions.Logger = ecsclientgowrapper.NewLevelLogger(ecsClientOptions.Logger, ecsClientOptions.LogLevel)
+	}
+
+	environment, internalClientOptions, err := toInternalClientOptions(ecsClientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	internalClientOptions.EcsConfigurationEventCallbackFunc = &callbackFunction
+
+	internalClient, err := ecsclientgowrapper.CreateEcsClient(environment, ecsClientOptions.Client, ecsClientOptions.ProjectTeams, internalClientOptions)
+	if err != nil {
+		return nil, newEcsError(ErrClientCreationFailed, "", "", err)
+	}
+
+	ecsClient := newEcsClient(internalClient, ecsClientOptions.Logger, opts)
+
+	callbackFunction = func(event ecsclientgowrapper.ECS_EVENT_TYPE, message string) {
+		ecsClient.handleEvent(EcsEvent{Type: event, Message: message})
+	}
+
+	return ecsClient, nil
+}
+
+// toInternalClientOptions maps the EcsClientOptions to the environment and options of the ecs C library wrapper
+func toInternalClientOptions(ecsClientOptions EcsClientOptions) (ecsclientgowrapper.ECS_ENVIRONMENT_TYPE, ecsclientgowrapper.EcsClientOptions, error) {
+	environment := ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_PRODUCTION
+	if ecsClientOptions.Environment != nil {
+		environment = *ecsClientOptions.Environment
+	}
+
+	if !environment.IsValid() {
+		return environment, ecsclientgowrapper.EcsClientOptions{}, newEcsError(ErrInvalidClientOptions, "", "", fmt.Errorf("unknown ECS environment '%v'", environment))
+	}
+
+	if ecsClientOptions.AuthenticationEnvironment != nil && !ecsClientOptions.AuthenticationEnvironment.IsValid() {
+		return environment, ecsclientgowrapper.EcsClientOptions{}, newEcsError(ErrInvalidClientOptions, "", "", fmt.Errorf("unknown ECS authentication environment '%v'", *ecsClientOptions.AuthenticationEnvironment))
+	}
+
 	targetFilters := make([]ecsclientgowrapper.EcsRequestIdentifier, len(ecsClientOptions.TargetFilters))
 
 	idx := 0
@@ -107,184 +403,667 @@ func NewEcsClient(ecsClientOptions EcsClientOptions) (*EcsClient, error) {
 	}
 
 	internalClientOptions := ecsclientgowrapper.EcsClientOptions{
-		DefaultConfigPath:                 ecsClientOptions.DefaultConfigPath,
-		DefaultGroupsPath:                 ecsClientOptions.DefaultGroupsPath,
-		DefaultRequestIdentifiers:         targetFilters,
-		X509Cert:                          ecsClientOptions.X509Cert,
-		TenantId:                          ecsClientOptions.TenantId,
-		ClientId:                          ecsClientOptions.ClientId,
-		AuthenticationEnvironment:         ecsClientOptions.AuthenticationEnvironment,
-		AuthenticationMethod:              ecsClientOptions.AuthenticationMethod,
-		EcsConfigurationEventCallbackFunc: &callbackFunction,
-		Logger:                            ecsClientOptions.Logger,
-		LogLevel:                          ecsClientOptions.LogLevel,
-		EnableExp:                         ecsClientOptions.EnableExp,
-	}
-
-	internalClient, err := ecsclientgowrapper.CreateEcsClient(ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_PRODUCTION, ecsClientOptions.Client, ecsClientOptions.ProjectTeams, internalClientOptions)
-	if err != nil {
-		return nil, err
+		DefaultConfigPath:         ecsClientOptions.DefaultConfigPath,
+		DefaultGroupsPath:         ecsClientOptions.DefaultGroupsPath,
+		DefaultRequestIdentifiers: targetFilters,
+		X509Cert:                  ecsClientOptions.X509Cert,
+		TenantId:                  ecsClientOptions.TenantId,
+		ClientId:                  ecsClientOptions.ClientId,
+		AuthenticationEnvironment: ecsClientOptions.AuthenticationEnvironment,
+		AuthenticationMethod:      ecsClientOptions.AuthenticationMethod,
+		Logger:                    ecsClientOptions.Logger,
+		LogLevel:                  ecsClientOptions.LogLevel,
+		EnableExp:                 ecsClientOptions.EnableExp,
 	}
 
+	return environment, internalClientOptions, nil
+}
+
+// NewEcsClient creates a new ecs client that fetches config from EcsConfigGetter - useful for mocking/testing
+func NewEcsClientFromConfigGetter(ecsConfigGetter EcsConfigGetter, logger ecsclientgowrapper.Logger, opts ...ClientOption) *EcsClient {
+	return newEcsClient(ecsConfigGetter, logger, opts)
+}
+
+// newEcsClient creates the EcsClient for internalEcsClient and applies the ClientOptions
+func newEcsClient(internalEcsClient EcsConfigGetter, logger ecsclientgowrapper.Logger, opts []ClientOption) *EcsClient {
 	ecsClient := &EcsClient{
-		internalEcsClient: internalClient,
-		logger:            ecsClientOptions.Logger,
+		internalEcsClient: internalEcsClient,
+		logger:            logger,
 		ecsOptionMonitors: make(map[any]*EcsOptionsMonitor),
+		now:               time.Now,
+		metrics:           noopMetricsRecorder{},
+		tracer:            noopTracer(),
+		environ:           os.Environ,
 	}
 
-	callbackFunction = func(event ecsclientgowrapper.ECS_EVENT_TYPE, message string) {
-		ecsClient.invokeOptionsUpdate(false)
+	for _, opt := range opts {
+		opt(ecsClient)
 	}
 
-	return ecsClient, nil
+	if ecsClient.overridesFile != "" {
+		ecsClient.watchOverridesFile()
+	}
+
+	return ecsClient
 }
 
-// NewEcsClient creates a new ecs client that fetches config from EcsConfigGetter - useful for mocking/testing
-func NewEcsClientFromConfigGetter(ecsConfigGetter EcsConfigGetter, logger ecsclientgowrapper.Logger) *EcsClient {
-	return &EcsClient{
-		internalEcsClient: ecsConfigGetter,
-		logger:            logger,
-		ecsOptionMonitors: make(map[any]*EcsOptionsMonitor),
+// Close stops delivering config updates, waits for in-flight updates to finish and the queued update events to be delivered to the
+// callbacks, and destroys the native ecs client (or closes the EcsConfigGetter if it implements io.Closer).
+// If ctx expires before that, ctx.Err() is returned and Close can be called again. All calls on the closed client return ErrClientClosed.
+// Close must not be called from within a callback, as it would wait for the calling callback until ctx expires, use CloseFromCallback there.
+func (ecsClient *EcsClient) Close(ctx context.Context) error {
+	return ecsClient.close(ctx, false)
+}
+
+// CloseFromCallback is like Close, but can be called from within a callback. It doesn't wait for the callbacks that are running,
+// including the calling one, whose remaining queued events are delivered after they returned.
+func (ecsClient *EcsClient) CloseFromCallback(ctx context.Context) error {
+	return ecsClient.close(ctx, true)
+}
+
+// close closes the client, fromCallback skips waiting for the running callbacks
+func (ecsClient *EcsClient) close(ctx context.Context, fromCallback bool) error {
+	ecsClient.lifecycleMutex.Lock()
+	if !ecsClient.closed && ecsClient.overridesWatchStop != nil {
+		close(ecsClient.overridesWatchStop)
+	}
+	ecsClient.closed = true
+	ecsClient.lifecycleMutex.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		ecsClient.inFlightUpdates.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	subscriptions := ecsClient.dispatchers()
+	for _, subscription := range subscriptions {
+		subscription.close()
+	}
+
+	// a callback that closes the client can't stop before CloseFromCallback returns
+	for _, subscription := range subscriptions {
+		if fromCallback && subscription.isDispatching() {
+			continue
+		}
+
+		select {
+		case <-subscription.stopped():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
+
+	ecsClient.destroyOnce.Do(func() {
+		switch internalEcsClient := ecsClient.internalEcsClient.(type) {
+		case nativeClientDestroyer:
+			ecsClient.destroyErr = internalEcsClient.DestroyClient()
+		case io.Closer:
+			ecsClient.destroyErr = internalEcsClient.Close()
+		}
+	})
+
+	return ecsClient.destroyErr
+}
+
+// dispatchers returns the subscriptions of all callbacks registered on the client
+func (ecsClient *EcsClient) dispatchers() []dispatcher {
+	var subscriptions []dispatcher
+
+	ecsClient.callbackFuncsMutex.RLock()
+	for _, listener := range ecsClient.ecsOptionMonitors {
+		for _, subscription := range listener.subscriptions() {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	ecsClient.callbackFuncsMutex.RUnlock()
+
+	for _, subscription := range ecsClient.eventSubscriptions() {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions
+}
+
+// beginUpdate registers an in-flight update and returns false if the client is closed. Each successful call must be followed by endUpdate.
+func (ecsClient *EcsClient) beginUpdate() bool {
+	ecsClient.lifecycleMutex.Lock()
+	defer ecsClient.lifecycleMutex.Unlock()
+
+	if ecsClient.closed {
+		return false
+	}
+
+	ecsClient.inFlightUpdates.Add(1)
+	return true
+}
+
+// endUpdate marks an in-flight update as finished
+func (ecsClient *EcsClient) endUpdate() {
+	ecsClient.inFlightUpdates.Done()
 }
 
 func (ecsClient *EcsClient) TriggerAllUpdateEventCallbacks() {
+	if !ecsClient.beginUpdate() {
+		return
+	}
+	defer ecsClient.endUpdate()
+
 	ecsClient.callbackFuncsMutex.Lock()
 	defer ecsClient.callbackFuncsMutex.Unlock()
 
+	metadata := ecsClient.LastMetadata()
 	for _, listener := range ecsClient.ecsOptionMonitors {
-		for _, fn := range listener.configUpdateEvents {
-			fn(nil)
-		}
+		event := listener.newUpdateEvent(metadata, nil)
+		event.Current = event.Previous
+		listener.notify(context.Background(), event)
 	}
 }
 
+// LastMetadata returns the metadata of the last config fetched from ECS, or an empty ConfigMetadata if no config has been fetched yet.
+// It has no Overrides, as they apply to single options.
+func (ecsClient *EcsClient) LastMetadata() ConfigMetadata {
+	if metadata := ecsClient.lastMetadata.Load(); metadata != nil {
+		return metadata.clone()
+	}
+
+	return ConfigMetadata{}
+}
+
+// invokeOptionsUpdate fetches the config and updates all options monitors, unless the client is closed
 func (ecsClient *EcsClient) invokeOptionsUpdate(isInitialUpdate bool) {
-	config, err := ecsClient.internalEcsClient.GetConfig(ecsclientgowrapper.EcsRequestIdentifiers{})
+	if !ecsClient.beginUpdate() {
+		return
+	}
+	defer ecsClient.endUpdate()
+
+	ecsClient.updateOptions(context.Background(), isInitialUpdate, ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED)
+}
+
+// GetConfigContext fetches the ECS config. If ctx is done before the config has been fetched, ctx.Err() is returned while the
+// fetch finishes in the background.
+func (ecsClient *EcsClient) GetConfigContext(ctx context.Context, ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error) {
+	if !ecsClient.beginUpdate() {
+		return "", ErrClientClosed
+	}
+	defer ecsClient.endUpdate()
+
+	config, err := ecsClient.fetchConfig(ctx, ecsRequestIdentifiers)
 	if err != nil {
-		ecsClient.logger.Log(ecsclientgowrapper.ECS_LOG_LEVEL_ERROR, "updating config failed")
+		return "", newEcsError(ErrFetchFailed, "", "", err)
+	}
 
-		ecsClient.callbackFuncsMutex.RLock()
-		defer ecsClient.callbackFuncsMutex.RUnlock()
-		for _, listener := range ecsClient.ecsOptionMonitors {
-			for _, fn := range listener.configUpdateEvents {
-				fn(err)
-			}
-		}
+	return config, nil
+}
 
-		return
+// fetchConfig fetches the config from the internalEcsClient and returns ctx.Err() if ctx is done first.
+// The caller must have registered the update with beginUpdate.
+func (ecsClient *EcsClient) fetchConfig(ctx context.Context, ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error) {
+	ctx, span := ecsClient.tracer.Start(ctx, SpanNameFetch)
+
+	start := time.Now()
+	config, err := ecsClient.fetchConfigFromGetter(ctx, ecsRequestIdentifiers)
+	ecsClient.metrics.ObserveFetch(time.Since(start), err)
+	endSpan(span, err)
+
+	return config, err
+}
+
+// fetchConfigFromGetter fetches the config from the EcsConfigGetter, bounded by ctx
+func (ecsClient *EcsClient) fetchConfigFromGetter(ctx context.Context, ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error) {
+	if contextConfigGetter, ok := ecsClient.internalEcsClient.(ContextConfigGetter); ok {
+		return contextConfigGetter.GetConfigContext(ctx, ecsRequestIdentifiers)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	type fetchResult struct {
+		config string
+		err    error
+	}
+
+	// the abandoned fetch still counts as in-flight update, so Close does not destroy the client while it is running
+	ecsClient.inFlightUpdates.Add(1)
+	result := make(chan fetchResult, 1)
+	go func() {
+		defer ecsClient.endUpdate()
+
+		config, err := ecsClient.internalEcsClient.GetConfig(ecsRequestIdentifiers)
+		result <- fetchResult{config: config, err: err}
+	}()
+
+	select {
+	case fetched := <-result:
+		return fetched.config, fetched.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// updateOptions fetches the config and updates all options monitors. The caller must have registered the update with beginUpdate.
+func (ecsClient *EcsClient) updateOptions(ctx context.Context, isInitialUpdate bool, eventType ecsclientgowrapper.ECS_EVENT_TYPE) {
+	ctx, span := ecsClient.tracer.Start(ctx, SpanNameUpdate, trace.WithAttributes(eventTypeAttribute(eventType), AttributeInitial.Bool(isInitialUpdate)))
+
+	config, metadata, fetchErr := ecsClient.fetchOptionsConfig(ctx)
+	span.SetAttributes(AttributeETag.String(metadata.ETag))
+	defer endSpan(span, fetchErr)
+
 	ecsClient.callbackFuncsMutex.RLock()
 	defer ecsClient.callbackFuncsMutex.RUnlock()
 	for _, listener := range ecsClient.ecsOptionMonitors {
-		err, updatedOptions := listener.optionsUpdateFunc(config, ecsClient.logger)
-		if err != nil {
-			ecsClient.logger.Log(ecsclientgowrapper.ECS_LOG_LEVEL_ERROR, fmt.Sprintf("on options update func failed with error: %v", err))
-			for _, fn := range listener.configUpdateEvents {
-				fn(err)
-			}
-			continue
+		ecsClient.applyConfig(ctx, listener, config, metadata, fetchErr, isInitialUpdate, eventType)
+	}
+}
+
+// loadInitialOptions fetches the config and updates only ecsOptionsMonitor, so adding an options monitor does not affect the others.
+// Returns the error of the update. The caller must have registered the update with beginUpdate.
+func (ecsClient *EcsClient) loadInitialOptions(ctx context.Context, ecsOptionsMonitor *EcsOptionsMonitor) (err error) {
+	attributes := append(optionAttributes(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName), eventTypeAttribute(ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED), AttributeInitial.Bool(true))
+	ctx, span := ecsClient.tracer.Start(ctx, SpanNameUpdate, trace.WithAttributes(attributes...))
+	defer func() { endSpan(span, err) }()
+
+	config, metadata, fetchErr := ecsClient.fetchOptionsConfig(ctx)
+	span.SetAttributes(AttributeETag.String(metadata.ETag))
+
+	if fetchErr != nil && ecsClient.snapshotDirectory != "" {
+		loadErr := ecsClient.loadLastKnownGood(ecsOptionsMonitor, fetchErr)
+		if loadErr == nil {
+			ecsOptionsMonitor.recordUpdate(ecsClient.now(), fetchErr)
+			span.SetAttributes(AttributeStale.Bool(true))
+			return nil
 		}
 
-		if updatedOptions {
-			if !isInitialUpdate {
-				ecsClient.logger.Log(ecsclientgowrapper.ECS_LOG_LEVEL_INFORMATION, "Received ECS update - calling config update event")
-			}
+		ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, "loading the last-known-good config snapshot failed",
+			append(optionLogAttrs(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName), errorLogAttrs(loadErr)...)...)
+	}
 
-			for _, fn := range listener.configUpdateEvents {
-				fn(err)
-			}
+	return ecsClient.applyConfig(ctx, ecsOptionsMonitor, config, metadata, fetchErr, true, ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED)
+}
+
+// fetchOptionsConfig fetches the config and stores its metadata. Returns the last metadata and an ErrFetchFailed error if the fetch failed.
+func (ecsClient *EcsClient) fetchOptionsConfig(ctx context.Context) (string, ConfigMetadata, error) {
+	config, err := ecsClient.fetchConfig(ctx, ecsclientgowrapper.EcsRequestIdentifiers{})
+	if err != nil {
+		err = newEcsError(ErrFetchFailed, "", "", err)
+		ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_ERROR, "updating config failed", errorLogAttrs(err)...)
+		return "", ecsClient.LastMetadata(), err
+	}
+
+	// malformed configs are reported by the options update funcs, so the metadata is only updated for valid configs
+	_, span := ecsClient.tracer.Start(ctx, SpanNameParse)
+	metadata, err := parseConfigMetadata(config)
+	if err == nil {
+		ecsClient.lastMetadata.Store(&metadata)
+		span.SetAttributes(AttributeETag.String(metadata.ETag))
+	}
+	endSpan(span, err)
+
+	return config, metadata, nil
+}
+
+// applyConfig updates the options monitor with the fetched config, or reports fetchErr if the fetch failed, and notifies its callbacks.
+// Returns the error of the update.
+func (ecsClient *EcsClient) applyConfig(ctx context.Context, listener *EcsOptionsMonitor, config string, metadata ConfigMetadata, fetchErr error, isInitialUpdate bool, eventType ecsclientgowrapper.ECS_EVENT_TYPE) (err error) {
+	attributes := append(optionAttributes(listener.projectTeam, listener.optionName),
+		AttributeETag.String(metadata.ETag), AttributeConfigID.String(metadata.ConfigID(listener.projectTeam)), eventTypeAttribute(eventType))
+	ctx, span := ecsClient.tracer.Start(ctx, SpanNameApply, trace.WithAttributes(attributes...))
+	defer func() { endSpan(span, err) }()
+
+	if fetchErr != nil {
+		listener.recordUpdate(ecsClient.now(), fetchErr)
+		event := listener.newUpdateEvent(metadata, fetchErr)
+		event.EventType = eventType
+		listener.notify(ctx, event)
+		return fetchErr
+	}
+
+	event, updatedOptions := listener.optionsUpdateFunc(config, metadata, ecsClient.logger)
+	event.EventType = eventType
+	event.FromCache = eventType == ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE
+	listener.recordUpdate(ecsClient.now(), event.Err)
+
+	changeMessage := "Received ECS update - calling config update event"
+	if isInitialUpdate {
+		changeMessage = ""
+	}
+
+	return ecsClient.completeUpdate(ctx, span, listener, event, updatedOptions, true, changeMessage)
+}
+
+// completeUpdate records the outcome of an update of the options monitor in the span and the metrics, logs it and notifies the callbacks.
+// fromECS is false if the update didn't fetch the config from ECS, then only its failure is reported to the metrics. changeMessage is
+// logged if the options changed, unless it is empty. Returns the error of the update.
+func (ecsClient *EcsClient) completeUpdate(ctx context.Context, span trace.Span, listener *EcsOptionsMonitor, event OptionsUpdateEvent, updatedOptions bool, fromECS bool, changeMessage string) error {
+	span.SetAttributes(AttributeChanged.Bool(updatedOptions))
+	if fromECS || event.Err != nil {
+		ecsClient.metrics.ObserveUpdate(listener.projectTeam, listener.optionName, updatedOptions, event.Err)
+	}
+	if event.Err != nil {
+		attrs := append(optionLogAttrs(listener.projectTeam, listener.optionName), errorLogAttrs(event.Err)...)
+		ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_ERROR, "on options update func failed", attrs...)
+		listener.notify(ctx, event)
+		return event.Err
+	}
+
+	if updatedOptions {
+		if changeMessage != "" {
+			attrs := append(optionLogAttrs(listener.projectTeam, listener.optionName), slog.String(LogKeyCheckSum, event.CheckSum))
+			ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_INFORMATION, changeMessage, attrs...)
 		}
+
+		listener.notify(ctx, event)
 	}
+
+	return nil
 }
 
-// RegisterUpdateEventCallbackFunc registers another callback function to an options monitor for a certain option TOptions
-func (ecsClient *EcsClient) RegisterUpdateEventCallbackFunc(options OptionsUpdateReceiver, configUpdateEvent EcsUpdateEventCallbackFunc) error {
-	ecsClient.callbackFuncsMutex.Lock()
-	defer ecsClient.callbackFuncsMutex.Unlock()
+// reapplyOptions applies the last option each options monitor received from ECS again, e.g. after the overrides have changed,
+// unless the client is closed
+func (ecsClient *EcsClient) reapplyOptions() {
+	if !ecsClient.beginUpdate() {
+		return
+	}
+	defer ecsClient.endUpdate()
+
+	ecsClient.callbackFuncsMutex.RLock()
+	defer ecsClient.callbackFuncsMutex.RUnlock()
+	for _, listener := range ecsClient.ecsOptionMonitors {
+		ecsClient.reapplyOption(context.Background(), listener)
+	}
+}
+
+// reapplyOption applies the last option the options monitor received from ECS again and notifies its callbacks if the options changed.
+// The update is reported as ECS_EVENT_CONFIGURATION_CHANGED with the metadata of the last received option. Options monitors that
+// haven't received an option yet are skipped.
+func (ecsClient *EcsClient) reapplyOption(ctx context.Context, listener *EcsOptionsMonitor) (err error) {
+	if !listener.hasReceivedOption() {
+		return nil
+	}
+
+	attributes := append(optionAttributes(listener.projectTeam, listener.optionName), eventTypeAttribute(ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED))
+	ctx, span := ecsClient.tracer.Start(ctx, SpanNameApply, trace.WithAttributes(attributes...))
+	defer func() { endSpan(span, err) }()
+
+	event, updatedOptions := listener.applyOptionFunc(nil)
+
+	// the health and metrics track the updates from ECS, so applying the same option again only records failures
+	if event.Err != nil {
+		listener.recordUpdate(ecsClient.now(), event.Err)
+	}
+
+	return ecsClient.completeUpdate(ctx, span, listener, event, updatedOptions, false, "Applied the changed overrides - calling config update event")
+}
+
+// Current returns the snapshot of the last config accepted by the options monitor registered for options, or nil if there is none
+func (ecsClient *EcsClient) Current(options OptionsUpdateReceiver) *OptionsSnapshot {
+	ecsClient.callbackFuncsMutex.RLock()
+	defer ecsClient.callbackFuncsMutex.RUnlock()
 
 	if ecsUpdateListener, ok := ecsClient.ecsOptionMonitors[options]; ok {
-		ecsUpdateListener.configUpdateEvents = append(ecsUpdateListener.configUpdateEvents, configUpdateEvent)
-	} else {
-		return fmt.Errorf("no OptionsMonitor for provided options are registered - configUpdateEvent would never get called")
+		return ecsUpdateListener.Current()
 	}
+
 	return nil
 }
 
+// RegisterUpdateEventCallbackFunc registers another callback function to an options monitor for a certain option TOptions.
+// The callback is called asynchronously, in the order of the config updates. The returned UnsubscribeFunc removes the callback again.
+func (ecsClient *EcsClient) RegisterUpdateEventCallbackFunc(options OptionsUpdateReceiver, configUpdateEvent EcsUpdateEventCallbackFunc, opts ...SubscriptionOption) (UnsubscribeFunc, error) {
+	return ecsClient.RegisterMetadataUpdateEventCallbackFunc(options, func(metadata ConfigMetadata, optionsUpdateError error) {
+		configUpdateEvent(optionsUpdateError)
+	}, opts...)
+}
+
+// RegisterMetadataUpdateEventCallbackFunc registers a callback function to an options monitor for a certain option TOptions that also receives
+// the metadata of the ECS config
+func (ecsClient *EcsClient) RegisterMetadataUpdateEventCallbackFunc(options OptionsUpdateReceiver, configUpdateEvent EcsMetadataUpdateEventCallbackFunc, opts ...SubscriptionOption) (UnsubscribeFunc, error) {
+	return ecsClient.RegisterOptionsUpdateEventCallbackFunc(options, func(event OptionsUpdateEvent) {
+		configUpdateEvent(event.Metadata, event.Err)
+	}, opts...)
+}
+
+// RegisterOptionsUpdateEventCallbackFunc registers a callback function to an options monitor for a certain option TOptions that receives the
+// previous and new config, the changed paths and the triggering ECS event of each update
+func (ecsClient *EcsClient) RegisterOptionsUpdateEventCallbackFunc(options OptionsUpdateReceiver, configUpdateEvent EcsOptionsUpdateEventCallbackFunc, opts ...SubscriptionOption) (UnsubscribeFunc, error) {
+	if !ecsClient.beginUpdate() {
+		return nil, ErrClientClosed
+	}
+	defer ecsClient.endUpdate()
+
+	ecsClient.callbackFuncsMutex.RLock()
+	defer ecsClient.callbackFuncsMutex.RUnlock()
+
+	if ecsUpdateListener, ok := ecsClient.ecsOptionMonitors[options]; ok {
+		return ecsUpdateListener.subscribe(configUpdateEvent, ecsClient.logger, ecsClient.tracer, opts...), nil
+	}
+
+	return nil, fmt.Errorf("%w - configUpdateEvent would never get called", ErrMonitorNotFound)
+}
+
+// RemoveOptionsMonitor removes the options monitor registered for options and all of its callbacks from the ecsClient.
+// The options are not updated anymore afterwards.
+func (ecsClient *EcsClient) RemoveOptionsMonitor(options OptionsUpdateReceiver) error {
+	if !ecsClient.removeOptionsMonitor(options, nil) {
+		return ErrMonitorNotFound
+	}
+
+	return nil
+}
+
+// removeOptionsMonitor removes the options monitor registered for options if it is ecsOptionsMonitor, or any options monitor if
+// ecsOptionsMonitor is nil. Returns whether an options monitor was removed.
+func (ecsClient *EcsClient) removeOptionsMonitor(options OptionsUpdateReceiver, ecsOptionsMonitor *EcsOptionsMonitor) bool {
+	ecsClient.callbackFuncsMutex.Lock()
+	defer ecsClient.callbackFuncsMutex.Unlock()
+
+	registeredOptionsMonitor, ok := ecsClient.ecsOptionMonitors[options]
+	if !ok || (ecsOptionsMonitor != nil && registeredOptionsMonitor != ecsOptionsMonitor) {
+		return false
+	}
+
+	delete(ecsClient.ecsOptionMonitors, options)
+	registeredOptionsMonitor.unsubscribeAll()
+
+	// the metrics of the option are only dropped if no other options monitor of the same option is left
+	for _, otherOptionsMonitor := range ecsClient.ecsOptionMonitors {
+		if otherOptionsMonitor.projectTeam == registeredOptionsMonitor.projectTeam && otherOptionsMonitor.optionName == registeredOptionsMonitor.optionName {
+			return true
+		}
+	}
+
+	ecsClient.metrics.ObserveRemove(registeredOptionsMonitor.projectTeam, registeredOptionsMonitor.optionName)
+	return true
+}
+
 // AddOptionsMonitorToEcsClient adds a TOptions struct to the ecsClient for monitoring. The ecsClient will update the values of the options and
-// call the callback function whenever a config update has been registered.
-func (ecsClient *EcsClient) AddOptionsMonitorToEcsClient(options OptionsUpdateReceiver, projectTeam string, optionName string) error {
-	var checkSum *string
-	var updateFunc ecsOptionsUpdateFunc = func(config string, logger ecsclientgowrapper.Logger) (error, bool) {
+// call the callback function whenever a config update has been registered. The returned UnsubscribeFunc removes the options monitor again,
+// it is also returned if the initial config was rejected, as the options monitor stays registered for later updates.
+func (ecsClient *EcsClient) AddOptionsMonitorToEcsClient(options OptionsUpdateReceiver, projectTeam string, optionName string) (UnsubscribeFunc, error) {
+	return ecsClient.AddOptionsMonitorContext(context.Background(), options, projectTeam, optionName)
+}
+
+// AddOptionsMonitorContext is like AddOptionsMonitorToEcsClient, but the initial config load is bounded by ctx. If ctx is done before the
+// initial config has been fetched, the options monitor is removed again and an error matching ctx.Err() is returned.
+func (ecsClient *EcsClient) AddOptionsMonitorContext(ctx context.Context, options OptionsUpdateReceiver, projectTeam string, optionName string) (UnsubscribeFunc, error) {
+	if !ecsClient.beginUpdate() {
+		return nil, ErrClientClosed
+	}
+	defer ecsClient.endUpdate()
+
+	ecsOptionsMonitor := &EcsOptionsMonitor{projectTeam: projectTeam, optionName: optionName, options: options}
+	redactedPaths := ecsClient.redactedPaths
+	if secretPathsProvider, ok := options.(SecretPathsProvider); ok {
+		redactedPaths = append(redactedPaths[:len(redactedPaths):len(redactedPaths)], secretPathsProvider.SecretPaths()...)
+	}
+	ecsOptionsMonitor.redactor = newRedactor(redactedPaths)
+	if defaultsProvider, ok := options.(DefaultsProvider); ok {
+		if err := ecsOptionsMonitor.setDefaults(defaultsProvider); err != nil {
+			return nil, err
+		}
+	}
+	if schemaProvider, ok := options.(SchemaProvider); ok {
+		if err := ecsOptionsMonitor.setSchema(schemaProvider); err != nil {
+			return nil, err
+		}
+	}
+	ecsOptionsMonitor.health = MonitorHealth{ProjectTeam: projectTeam, OptionName: optionName}
+	ecsOptionsMonitor.optionsUpdateFunc = func(config string, metadata ConfigMetadata, logger ecsclientgowrapper.Logger) (OptionsUpdateEvent, bool) {
 		var fullConfig map[string]interface{}
 		if err := json.Unmarshal([]byte(config), &fullConfig); err != nil {
-			return fmt.Errorf("failed to unmarshal ecs config"), false
+			return ecsOptionsMonitor.newUpdateEvent(metadata, newEcsError(ErrInvalidConfig, projectTeam, optionName, err)), false
 		}
 
-		clientConfig, ok := fullConfig[projectTeam]
-		if !ok {
-			return fmt.Errorf("failed to find projectTeam property '%v'", projectTeam), false
+		// a missing project team or option falls back to the default options, if there are any
+		var optionConfig interface{}
+		clientConfig, found := fullConfig[projectTeam]
+		if !found && !ecsOptionsMonitor.hasDefaults {
+			return ecsOptionsMonitor.newUpdateEvent(metadata, newEcsError(ErrProjectTeamNotFound, projectTeam, optionName, nil)), false
 		}
 
-		typedClientConfig, ok := clientConfig.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("failed to parse property '%v'", projectTeam), false
+		if found {
+			typedClientConfig, ok := clientConfig.(map[string]interface{})
+			if !ok {
+				return ecsOptionsMonitor.newUpdateEvent(metadata, newEcsError(ErrInvalidConfig, projectTeam, optionName, fmt.Errorf("failed to parse property '%v'", projectTeam))), false
+			}
+
+			optionConfig, found = typedClientConfig[optionName]
+			if !found && !ecsOptionsMonitor.hasDefaults {
+				return ecsOptionsMonitor.newUpdateEvent(metadata, newEcsError(ErrOptionNotFound, projectTeam, optionName, nil)), false
+			}
 		}
 
-		optionConfig, ok := typedClientConfig[optionName]
-		if !ok {
-			return fmt.Errorf("failed to parse property '%v' in '%v'", optionName, projectTeam), false
+		return ecsOptionsMonitor.applyOptionFunc(&receivedOption{value: optionConfig, found: found, metadata: metadata})
+	}
+	ecsOptionsMonitor.applyOptionFunc = func(received *receivedOption) (OptionsUpdateEvent, bool) {
+		ecsOptionsMonitor.updateMutex.Lock()
+		defer ecsOptionsMonitor.updateMutex.Unlock()
+
+		if received == nil {
+			received = ecsOptionsMonitor.lastReceived
+			if received == nil {
+				return ecsOptionsMonitor.newUpdateEvent(ecsClient.LastMetadata(), nil), false
+			}
 		}
+		ecsOptionsMonitor.lastReceived = received
+		metadata := received.metadata
+
+		// the option as received from ECS is persisted, so the defaults and overrides of the next run apply to the snapshot
+		optionConfig, appliedOverrides := ecsClient.effectiveOption(ecsOptionsMonitor, received.value, received.found)
 
 		jsonOpts, err := json.Marshal(optionConfig)
 		if err != nil {
-			return fmt.Errorf("failed to get bytes for options '%v', err: %v", optionName, err), false
+			return ecsOptionsMonitor.newUpdateEvent(metadata, newEcsError(ErrInvalidConfig, projectTeam, optionName, err)), false
 		}
 
 		newCheckSum, err := getCheckSum(jsonOpts)
 		if err != nil {
-			return err, false
+			return ecsOptionsMonitor.newUpdateEvent(metadata, newEcsError(ErrInvalidConfig, projectTeam, optionName, err)), false
 		}
 
+		event := ecsOptionsMonitor.newUpdateEvent(metadata, nil)
+		event.Overrides = appliedOverrides
+		event.Metadata.Overrides = appliedOverrides
+
 		// only do the update if we see that the checkSum has changed:
-		if checkSum == nil || *checkSum != newCheckSum {
-			err = options.OnOptionsUpdateReceived(jsonOpts)
+		if event.Previous == nil || event.CheckSum != newCheckSum {
+			err = ecsOptionsMonitor.validateSchema(optionConfig)
+			if err == nil {
+				err = callOnOptionsUpdateReceived(options, jsonOpts)
+			}
 			if err != nil {
-				return fmt.Errorf("failed options update: '%v'", err), false
+				event.Err = newEcsError(optionsUpdateErrorKind(err), projectTeam, optionName, err)
+				return event, false
 			}
 
-			checkSum = &newCheckSum
-			ecsClient.logger.Log(ecsclientgowrapper.ECS_LOG_LEVEL_INFORMATION, fmt.Sprintf("Received ECS config: %v", string(jsonOpts)))
-			return nil, true
+			snapshot := &OptionsSnapshot{Value: jsonOpts, CheckSum: newCheckSum, ConfigID: metadata.ConfigID(projectTeam), Stale: received.stale}
+			ecsOptionsMonitor.snapshot.Store(snapshot)
+			if !received.stale {
+				ecsClient.persistSnapshot(ecsOptionsMonitor, received.value, received.found, snapshot.ConfigID)
+			}
+			attrs := append(optionLogAttrs(projectTeam, optionName), slog.String(LogKeyCheckSum, newCheckSum), slog.String(LogKeyConfigID, metadata.ConfigID(projectTeam)))
+			if !received.found {
+				attrs = append(attrs, slog.Bool(LogKeyDefaultsOnly, true))
+			}
+			if len(appliedOverrides) > 0 {
+				attrs = append(attrs, slog.String(LogKeyOverrides, overridesLogValue(appliedOverrides)))
+			}
+			if !ecsClient.checksumOnlyLogging {
+				attrs = append(attrs, slog.String(LogKeyConfig, string(ecsOptionsMonitor.redactor.redact(jsonOpts))))
+			}
+			ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_INFORMATION, "Received ECS config", attrs...)
+
+			event.Current = jsonOpts
+			event.CheckSum = newCheckSum
+			if event.ChangedPaths, err = jsonChangedPaths(event.Previous, event.Current); err != nil {
+				ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, "failed to compute the changed paths",
+					append(optionLogAttrs(projectTeam, optionName), errorLogAttrs(err)...)...)
+			}
+
+			return event, true
 		}
 
-		return nil, false
+		// ECS confirmed the last-known-good snapshot, so it isn't stale anymore
+		if current := ecsOptionsMonitor.snapshot.Load(); current.Stale && !received.stale {
+			snapshot := &OptionsSnapshot{Value: current.Value, CheckSum: current.CheckSum, ConfigID: metadata.ConfigID(projectTeam)}
+			ecsOptionsMonitor.snapshot.Store(snapshot)
+			ecsClient.persistSnapshot(ecsOptionsMonitor, received.value, received.found, snapshot.ConfigID)
+		}
+
+		return event, false
 	}
 
 	ecsClient.callbackFuncsMutex.Lock()
 	if _, ok := ecsClient.ecsOptionMonitors[options]; ok {
 		ecsClient.callbackFuncsMutex.Unlock()
-		return fmt.Errorf("there is already an options monitor registered for the same options")
+		return nil, ErrMonitorAlreadyRegistered
 	}
 
-	var initialCallbackError error
-	initCallbackFunc := func(optionsUpdateError error) {
-		if optionsUpdateError != nil {
-			initialCallbackError = optionsUpdateError
-			return
-		}
+	ecsClient.ecsOptionMonitors[options] = ecsOptionsMonitor
+	ecsClient.callbackFuncsMutex.Unlock()
+
+	initialUpdateError := ecsClient.loadInitialOptions(ctx, ecsOptionsMonitor)
+
+	if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(initialUpdateError, ctxErr) {
+		ecsClient.removeOptionsMonitor(options, ecsOptionsMonitor)
+		return nil, initialUpdateError
 	}
 
-	ecsClient.ecsOptionMonitors[options] = &EcsOptionsMonitor{
-		optionsUpdateFunc:  updateFunc,
-		configUpdateEvents: []EcsUpdateEventCallbackFunc{initCallbackFunc},
+	return func() {
+		ecsClient.removeOptionsMonitor(options, ecsOptionsMonitor)
+	}, initialUpdateError
+}
+
+// callOnOptionsUpdateReceived calls OnOptionsUpdateReceived of options and converts a panic into a *PanicError
+func callOnOptionsUpdateReceived(options OptionsUpdateReceiver, bytes []byte) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = newPanicError(recovered)
+		}
+	}()
+
+	return options.OnOptionsUpdateReceived(bytes)
+}
+
+// optionsUpdateErrorKind classifies an error returned by OnOptionsUpdateReceived: failures to decode the options are
+// reported as ErrInvalidConfig, panics as ErrPanicked and everything else as a rejection by the validation.
+func optionsUpdateErrorKind(err error) error {
+	if errors.Is(err, ErrInvalidConfig) {
+		return ErrInvalidConfig
 	}
 
-	ecsClient.callbackFuncsMutex.Unlock()
-	ecsClient.invokeOptionsUpdate(true)
+	if errors.Is(err, ErrPanicked) {
+		return ErrPanicked
+	}
 
-	return initialCallbackError
+	return ErrValidationFailed
 }
 
 func getCheckSum(byteArr []byte) (string, error) {
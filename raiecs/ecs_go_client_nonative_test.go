@@ -0,0 +1,18 @@
+//go:build !cgo || noecsnative
+
+package ecsgoclient
+
+import (
+	"testing"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that creating a native client fails with ErrNativeUnavailable when the native library is not part of the build
+func TestEcsGoClientNativeUnavailable(t *testing.T) {
+	_, err := NewEcsClient(EcsClientOptions{Client: "TestClient", ProjectTeams: []string{"TestProjectTeam"}})
+	require.ErrorIs(t, err, ErrClientCreationFailed)
+	require.ErrorIs(t, err, ecsclientgowrapper.ErrNativeUnavailable)
+}
@@ -1,10 +1,12 @@
 package ecsgoclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/raiecs/ecsclientgowrapper"
 
@@ -136,7 +138,7 @@ func TestEcsGoClientInitialConfigReceived(t *testing.T) {
 
 	testConfig := &TestConfig{}
 
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.NoError(t, err)
 	require.Equal(t, "TestValue1", testConfig.TestProperty)
 	require.Equal(t, 1, testConfig.TestIntegerWithMaxValue100)
@@ -150,7 +152,7 @@ func TestEcsGoClientInvalidInitialConfigReceived(t *testing.T) {
 	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
 
 	testConfig := &TestConfig{}
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.Error(t, err)
 	require.Equal(t, "", testConfig.TestProperty)
 }
@@ -164,7 +166,7 @@ func TestEcsGoClientErrorReceived(t *testing.T) {
 
 	testConfig := &TestConfig{}
 
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.Error(t, err)
 	require.Equal(t, "", testConfig.TestProperty)
 }
@@ -178,14 +180,14 @@ func TestEcsGoClientConfigUpdateReceived(t *testing.T) {
 
 	testConfig := &TestConfig{}
 
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 
 	require.NoError(t, err)
 	require.Equal(t, "TestValue1", testConfig.TestProperty)
 	require.Equal(t, 1, testConfig.TestIntegerWithMaxValue100)
 
 	var optionsUpdateError error
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
 		optionsUpdateError = innerOptionsUpdateError
 	})
 	require.NoError(t, err)
@@ -194,6 +196,7 @@ func TestEcsGoClientConfigUpdateReceived(t *testing.T) {
 	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.NoError(t, optionsUpdateError)
 	require.Equal(t, "TestValue2", testConfig.TestProperty)
 	require.Equal(t, 2, testConfig.TestIntegerWithMaxValue100)
@@ -209,13 +212,13 @@ func TestEcsGoClientInvalidConfigUpdateReceived(t *testing.T) {
 
 	testConfig := &TestConfig{}
 
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.NoError(t, err)
 	require.Equal(t, "TestValue1", testConfig.TestProperty)
 	require.Equal(t, 1, testConfig.TestIntegerWithMaxValue100)
 
 	var optionsUpdateError error
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
 		optionsUpdateError = innerOptionsUpdateError
 	})
 	require.NoError(t, err)
@@ -224,6 +227,7 @@ func TestEcsGoClientInvalidConfigUpdateReceived(t *testing.T) {
 	ecsConfigGetter.On("GetConfig", mock.Anything).Return(invalidConfigUpdate, nil)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Error(t, optionsUpdateError)
 	require.Equal(t, "TestValue1", testConfig.TestProperty)
 	require.Equal(t, 1, testConfig.TestIntegerWithMaxValue100)
@@ -238,13 +242,13 @@ func TestEcsGoClientConfigUpdateErrorReceived(t *testing.T) {
 	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
 
 	testConfig := &TestConfig{}
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.NoError(t, err)
 	require.Equal(t, "TestValue1", testConfig.TestProperty)
 	require.Equal(t, 1, testConfig.TestIntegerWithMaxValue100)
 
 	var optionsUpdateError error
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
 		optionsUpdateError = innerOptionsUpdateError
 	})
 	require.NoError(t, err)
@@ -253,6 +257,7 @@ func TestEcsGoClientConfigUpdateErrorReceived(t *testing.T) {
 	ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("some error"))
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Error(t, optionsUpdateError)
 	require.Equal(t, "TestValue1", testConfig.TestProperty)
 	require.Equal(t, 1, testConfig.TestIntegerWithMaxValue100)
@@ -266,11 +271,11 @@ func TestEcsGoClientConfigUpdateEventsCallback(t *testing.T) {
 	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
 
 	testConfig := &TestConfig{}
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.NoError(t, err)
 
 	configUpdateCounter := 0
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
 		if innerOptionsUpdateError == nil {
 			configUpdateCounter++
 		}
@@ -281,12 +286,14 @@ func TestEcsGoClientConfigUpdateEventsCallback(t *testing.T) {
 	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 1, configUpdateCounter)
 
 	configUpdateEvent2.Unset()
 	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 2, configUpdateCounter)
 }
 
@@ -298,11 +305,11 @@ func TestEcsGoClientConfigUpdateEventsCallbackWithErrors(t *testing.T) {
 	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
 
 	testConfig := &TestConfig{}
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.NoError(t, err)
 
 	configUpdateCounter := 0
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
 		if innerOptionsUpdateError == nil {
 			configUpdateCounter++
 		}
@@ -313,18 +320,21 @@ func TestEcsGoClientConfigUpdateEventsCallbackWithErrors(t *testing.T) {
 	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(invalidConfigUpdate, nil)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 0, configUpdateCounter)
 
 	configUpdateEvent2.Unset()
 	configUpdateEvent3 := ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("some error"))
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 0, configUpdateCounter)
 
 	configUpdateEvent3.Unset()
 	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 1, configUpdateCounter)
 }
 
@@ -337,11 +347,11 @@ func TestEcsGoClientConfigUpdateEventsCallbackSameConfig(t *testing.T) {
 
 	testConfig := &TestConfig{}
 
-	err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
 	require.NoError(t, err)
 
 	configUpdateCounter := 0
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
 		if innerOptionsUpdateError == nil {
 			configUpdateCounter++
 		}
@@ -349,8 +359,333 @@ func TestEcsGoClientConfigUpdateEventsCallbackSameConfig(t *testing.T) {
 	require.NoError(t, err)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 0, configUpdateCounter)
 
 	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
 	require.Equal(t, 0, configUpdateCounter)
 }
+
+// Tests that the ECS environment defaults to production if no environment is requested
+func TestEcsGoClientOptionsDefaultEnvironment(t *testing.T) {
+	environment, _, err := toInternalClientOptions(EcsClientOptions{})
+	require.NoError(t, err)
+	require.Equal(t, ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_PRODUCTION, environment)
+}
+
+// Tests that the requested ECS environment and options are passed to the ecs C library wrapper
+func TestEcsGoClientOptionsMapping(t *testing.T) {
+	for _, requestedEnvironment := range []ecsclientgowrapper.ECS_ENVIRONMENT_TYPE{
+		ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_INTEGRATION,
+		ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_PRODUCTION,
+		ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_GCCMOD,
+		ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_MOONCAKE,
+	} {
+		requestedEnvironment := requestedEnvironment
+		authenticationEnvironment := ecsclientgowrapper.ECS_ENVIRONMENT_TYPE_GCCH
+
+		environment, internalClientOptions, err := toInternalClientOptions(EcsClientOptions{
+			Environment:               &requestedEnvironment,
+			AuthenticationEnvironment: &authenticationEnvironment,
+			TargetFilters:             map[string][]string{EnvironmentRequestIdentifierName: {"TestEnvironment"}},
+			TenantId:                  "TestTenant",
+			LogLevel:                  ecsclientgowrapper.ECS_LOG_LEVEL_WARNING,
+		})
+		require.NoError(t, err)
+		require.Equal(t, requestedEnvironment, environment)
+		require.Equal(t, &authenticationEnvironment, internalClientOptions.AuthenticationEnvironment)
+		require.Equal(t, ecsclientgowrapper.EcsRequestIdentifiers{{Name: EnvironmentRequestIdentifierName, Values: []string{"TestEnvironment"}}}, internalClientOptions.DefaultRequestIdentifiers)
+		require.Equal(t, "TestTenant", internalClientOptions.TenantId)
+		require.Equal(t, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, internalClientOptions.LogLevel)
+	}
+}
+
+// Tests that unknown ECS environments are rejected
+func TestEcsGoClientOptionsInvalidEnvironment(t *testing.T) {
+	invalidEnvironment := ecsclientgowrapper.ECS_ENVIRONMENT_TYPE(7)
+	_, _, err := toInternalClientOptions(EcsClientOptions{Environment: &invalidEnvironment})
+	require.Error(t, err)
+
+	_, _, err = toInternalClientOptions(EcsClientOptions{AuthenticationEnvironment: &invalidEnvironment})
+	require.Error(t, err)
+}
+
+type destroyableConfigGetter struct {
+	mockConfigGetter
+}
+
+func (destroyableConfigGetter *destroyableConfigGetter) DestroyClient() error {
+	args := destroyableConfigGetter.Called()
+	return args.Error(0)
+}
+
+// Tests that closing the client destroys the native client once and rejects later calls
+func TestEcsGoClientClose(t *testing.T) {
+	ecsConfigGetter := destroyableConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+	ecsConfigGetter.On("DestroyClient").Return(nil).Once()
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	configUpdateCounter := 0
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		configUpdateCounter++
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ecsClientInstance.Close(context.Background()))
+	require.NoError(t, ecsClientInstance.Close(context.Background()))
+	ecsConfigGetter.AssertNumberOfCalls(t, "DestroyClient", 1)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	ecsClientInstance.TriggerAllUpdateEventCallbacks()
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, 0, configUpdateCounter)
+
+	_, err = ecsClientInstance.AddOptionsMonitorToEcsClient(&TestConfig{}, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, ErrClientClosed)
+
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {})
+	require.ErrorIs(t, err, ErrClientClosed)
+}
+
+// Tests that Close waits for in-flight updates before destroying the native client
+func TestEcsGoClientCloseWaitsForInFlightUpdates(t *testing.T) {
+	ecsConfigGetter := destroyableConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+	ecsConfigGetter.On("DestroyClient").Return(nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	fetchStarted := make(chan struct{})
+	releaseFetch := make(chan struct{})
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Run(func(args mock.Arguments) {
+		close(fetchStarted)
+		<-releaseFetch
+	}).Return(validConfigUpdate2, nil)
+
+	updateDone := make(chan struct{})
+	go func() {
+		ecsClientInstance.invokeOptionsUpdate(false)
+		close(updateDone)
+	}()
+	<-fetchStarted
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, ecsClientInstance.Close(ctx), context.DeadlineExceeded)
+	ecsConfigGetter.AssertNotCalled(t, "DestroyClient")
+
+	close(releaseFetch)
+	<-updateDone
+	require.NoError(t, ecsClientInstance.Close(context.Background()))
+	ecsConfigGetter.AssertNumberOfCalls(t, "DestroyClient", 1)
+	require.Equal(t, "TestValue2", testConfig.TestProperty)
+}
+
+// Tests that CloseFromCallback can be called from within an update callback
+func TestEcsGoClientCloseFromCallback(t *testing.T) {
+	ecsConfigGetter := destroyableConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+	ecsConfigGetter.On("DestroyClient").Return(nil).Once()
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	closeErr := make(chan error, 1)
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		closeErr <- ecsClientInstance.CloseFromCallback(ctx)
+	})
+	require.NoError(t, err)
+
+	ecsClientInstance.TriggerAllUpdateEventCallbacks()
+	require.NoError(t, <-closeErr)
+	ecsConfigGetter.AssertNumberOfCalls(t, "DestroyClient", 1)
+}
+
+// Tests that the initial config load returns the context error when the fetch does not finish in time
+func TestEcsGoClientAddOptionsMonitorContextTimeout(t *testing.T) {
+	releaseFetch := make(chan struct{})
+	ecsConfigGetter := destroyableConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Run(func(args mock.Arguments) {
+		<-releaseFetch
+	}).Return(validConfigUpdate1, nil)
+	ecsConfigGetter.On("DestroyClient").Return(nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := ecsClientInstance.AddOptionsMonitorContext(ctx, testConfig, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, ErrFetchFailed)
+	require.Equal(t, "", testConfig.TestProperty)
+
+	_, err = ecsClientInstance.GetConfigContext(ctx, ecsclientgowrapper.EcsRequestIdentifiers{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the abandoned fetches are still in flight and keep the client from being destroyed
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer closeCancel()
+	require.ErrorIs(t, ecsClientInstance.Close(closeCtx), context.DeadlineExceeded)
+
+	close(releaseFetch)
+	require.NoError(t, ecsClientInstance.Close(context.Background()))
+	require.Equal(t, "", testConfig.TestProperty)
+	ecsConfigGetter.AssertNumberOfCalls(t, "DestroyClient", 1)
+
+	configUpdateEvent1.Unset()
+}
+
+// Tests that the options monitor is removed after a timed out initial load, so it can be added again
+func TestEcsGoClientAddOptionsMonitorContextRetry(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ecsClientInstance.AddOptionsMonitorContext(ctx, testConfig, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, "", testConfig.TestProperty)
+
+	_, err = ecsClientInstance.AddOptionsMonitorContext(context.Background(), testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, "TestValue2", testConfig.TestProperty)
+
+	config, err := ecsClientInstance.GetConfigContext(context.Background(), ecsclientgowrapper.EcsRequestIdentifiers{})
+	require.NoError(t, err)
+	require.Equal(t, validConfigUpdate2, config)
+}
+
+// Tests that unsubscribed callbacks and removed options monitors don't receive updates anymore
+func TestEcsGoClientUnsubscribe(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	removeOptionsMonitor, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	firstCallbackCounter, secondCallbackCounter := 0, 0
+	unsubscribeFirst, err := ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		firstCallbackCounter++
+	})
+	require.NoError(t, err)
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		secondCallbackCounter++
+	})
+	require.NoError(t, err)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+
+	configUpdateEvent1.Unset()
+	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, 0, firstCallbackCounter)
+	require.Equal(t, 1, secondCallbackCounter)
+
+	removeOptionsMonitor()
+	require.Nil(t, ecsClientInstance.Current(testConfig))
+	require.ErrorIs(t, ecsClientInstance.RemoveOptionsMonitor(testConfig), ErrMonitorNotFound)
+
+	configUpdateEvent2.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, 1, secondCallbackCounter)
+	require.Equal(t, "TestValue2", testConfig.TestProperty)
+
+	// the options can be monitored again, and the stale UnsubscribeFunc must not remove the new options monitor
+	_, err = ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, "TestValue1", testConfig.TestProperty)
+
+	removeOptionsMonitor()
+	require.NotNil(t, ecsClientInstance.Current(testConfig))
+	require.NoError(t, ecsClientInstance.RemoveOptionsMonitor(testConfig))
+	require.Nil(t, ecsClientInstance.Current(testConfig))
+}
+
+// waitForDispatch blocks until the queued events of all callbacks of ecsClient have been delivered
+func waitForDispatch(ecsClient *EcsClient) {
+	for _, subscription := range ecsClient.dispatchers() {
+		subscription.waitIdle()
+	}
+}
+
+// Tests that the initial load of an options monitor only evaluates the new options monitor and returns its own error
+func TestEcsGoClientInitialLoadMultipleMonitors(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`
+	{
+		"TestProjectTeam":
+		{
+			"ConfigName": {"TestProperty": "TestValue1", "TestIntegerWithMaxValue100": 1},
+			"InvalidConfigName": {"TestProperty": "TestValue", "TestIntegerWithMaxValue100": 200}
+		}
+	}`, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	validConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(validConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	validConfigUpdateCounter := 0
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(validConfig, func(innerOptionsUpdateError error) {
+		validConfigUpdateCounter++
+	})
+	require.NoError(t, err)
+
+	invalidConfig := &TestConfig{}
+	_, err = ecsClientInstance.AddOptionsMonitorToEcsClient(invalidConfig, "TestProjectTeam", "InvalidConfigName")
+	require.ErrorIs(t, err, ErrValidationFailed)
+
+	missingConfig := &TestConfig{}
+	_, err = ecsClientInstance.AddOptionsMonitorToEcsClient(missingConfig, "TestProjectTeam", "MissingConfigName")
+	require.ErrorIs(t, err, ErrOptionNotFound)
+
+	otherValidConfig := &TestConfig{}
+	_, err = ecsClientInstance.AddOptionsMonitorToEcsClient(otherValidConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, "TestValue1", otherValidConfig.TestProperty)
+
+	// each initial load fetched the config, but only the new options monitor was evaluated
+	waitForDispatch(ecsClientInstance)
+	ecsConfigGetter.AssertNumberOfCalls(t, "GetConfig", 4)
+	require.Equal(t, 0, validConfigUpdateCounter)
+
+	// no internal callbacks are left behind by the initial loads
+	require.Len(t, ecsClientInstance.ecsOptionMonitors[validConfig].subscriptions(), 1)
+	for _, options := range []*TestConfig{invalidConfig, missingConfig, otherValidConfig} {
+		require.Empty(t, ecsClientInstance.ecsOptionMonitors[options].subscriptions())
+	}
+}
@@ -0,0 +1,159 @@
+package ecsclientgowrapper
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+)
+
+// clientCallbacks are the Go callbacks of a single EcsClient instance.
+type clientCallbacks struct {
+	eventCallbackFunc *EcsConfigurationEventCallbackFunc
+	logger            Logger
+}
+
+// callbackRegistry maps the native ECS client handles to the callbacks of the EcsClient they belong to, so multiple
+// clients can coexist in one process.
+//
+// The native lib does not pass the client handle to the log callback, so log messages are routed to the loggers of all
+// registered clients and of the client that is currently being created.
+type callbackRegistry struct {
+	mutex sync.RWMutex
+
+	// createMutex serializes client creation, so events of unknown handles that fire before ecs_create_client returns can be
+	// attributed to pending. They are not registered, as they may also come from a client that is being destroyed.
+	createMutex sync.Mutex
+	pending     *clientCallbacks
+
+	clients map[uintptr]*clientCallbacks
+	handles []uintptr
+}
+
+var nativeCallbackRegistry = &callbackRegistry{clients: make(map[uintptr]*clientCallbacks)}
+
+// beginCreate marks callbacks as belonging to the client that is currently being created. It must be followed by endCreate.
+func (registry *callbackRegistry) beginCreate(callbacks *clientCallbacks) {
+	registry.createMutex.Lock()
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.pending = callbacks
+}
+
+// endCreate registers the pending callbacks for the created handle, or drops them if the creation failed.
+func (registry *callbackRegistry) endCreate(handle uintptr, succeeded bool) {
+	defer registry.createMutex.Unlock()
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if succeeded {
+		registry.registerLocked(handle, registry.pending)
+	}
+
+	registry.pending = nil
+}
+
+// registerLocked registers callbacks for handle. The caller must hold the mutex.
+func (registry *callbackRegistry) registerLocked(handle uintptr, callbacks *clientCallbacks) {
+	if _, ok := registry.clients[handle]; !ok {
+		registry.handles = append(registry.handles, handle)
+	}
+
+	registry.clients[handle] = callbacks
+}
+
+// unregister removes the callbacks of handle, no callbacks are routed to them afterwards.
+func (registry *callbackRegistry) unregister(handle uintptr) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.unregisterLocked(handle)
+}
+
+// unregisterLocked removes the callbacks of handle. The caller must hold the mutex.
+func (registry *callbackRegistry) unregisterLocked(handle uintptr) {
+	delete(registry.clients, handle)
+	for i, registeredHandle := range registry.handles {
+		if registeredHandle == handle {
+			registry.handles = append(registry.handles[:i], registry.handles[i+1:]...)
+			break
+		}
+	}
+}
+
+// eventCallbackFunc returns the event callback of the client with the given handle, or nil if there is none.
+func (registry *callbackRegistry) eventCallbackFunc(handle uintptr) *EcsConfigurationEventCallbackFunc {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	callbacks, ok := registry.clients[handle]
+	if !ok {
+		// the event fired while the client is still being created, so its handle is not yet registered
+		if registry.pending == nil {
+			return nil
+		}
+
+		callbacks = registry.pending
+	}
+
+	return callbacks.eventCallbackFunc
+}
+
+// loggers returns the distinct loggers native log messages are routed to, the one of the client that is being created first.
+func (registry *callbackRegistry) loggers() []Logger {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	var loggers []Logger
+	addLogger := func(logger Logger) {
+		if logger == nil {
+			return
+		}
+
+		// clients often share a logger, which must not receive every message multiple times
+		if reflect.TypeOf(logger).Comparable() {
+			for _, added := range loggers {
+				if reflect.TypeOf(added).Comparable() && added == logger {
+					return
+				}
+			}
+		}
+		loggers = append(loggers, logger)
+	}
+
+	if registry.pending != nil {
+		addLogger(registry.pending.logger)
+	}
+	for _, handle := range registry.handles {
+		addLogger(registry.clients[handle].logger)
+	}
+
+	return loggers
+}
+
+// log passes a native log message to the loggers of all clients. A panicking logger doesn't keep the message from the others.
+func (registry *callbackRegistry) log(logLevel ECS_LOG_LEVEL, msg string) {
+	for _, logger := range registry.loggers() {
+		func() {
+			// the panic can't be logged, as it has come from a logger
+			defer func() {
+				_ = recover()
+			}()
+
+			logger.Log(logLevel, msg)
+		}()
+	}
+}
+
+// recoverPanic recovers a panic of a callback invoked by the native lib, which would otherwise crash the process, and logs it with
+// its stack trace. It must be deferred directly by the callback.
+func (registry *callbackRegistry) recoverPanic(callbackName string) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	registry.log(ECS_LOG_LEVEL_CRITICAL, fmt.Sprintf("ecs %v panicked: %v\n%s", callbackName, recovered, debug.Stack()))
+}
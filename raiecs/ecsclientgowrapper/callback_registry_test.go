@@ -0,0 +1,126 @@
+package ecsclientgowrapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (recordingLogger *recordingLogger) Log(logLevel ECS_LOG_LEVEL, msg string) {
+	recordingLogger.messages = append(recordingLogger.messages, msg)
+}
+
+// Tests that the callbacks of multiple clients are routed by their handle and removed on unregister
+func TestCallbackRegistryRoutesByHandle(t *testing.T) {
+	registry := &callbackRegistry{clients: make(map[uintptr]*clientCallbacks)}
+
+	var receivedEvents []string
+	newCallbacks := func(name string, logger Logger) *clientCallbacks {
+		var eventCallbackFunc EcsConfigurationEventCallbackFunc = func(event ECS_EVENT_TYPE, message string) {
+			receivedEvents = append(receivedEvents, name)
+		}
+		return &clientCallbacks{eventCallbackFunc: &eventCallbackFunc, logger: logger}
+	}
+
+	logger1, logger2 := &recordingLogger{}, &recordingLogger{}
+
+	registry.beginCreate(newCallbacks("client1", logger1))
+	registry.endCreate(1, true)
+	registry.beginCreate(newCallbacks("client2", logger2))
+	require.Equal(t, []Logger{logger2, logger1}, registry.loggers())
+	registry.endCreate(2, true)
+
+	(*registry.eventCallbackFunc(2))(ECS_EVENT_CONFIGURATION_CHANGED, "")
+	(*registry.eventCallbackFunc(1))(ECS_EVENT_CONFIGURATION_CHANGED, "")
+	require.Equal(t, []string{"client2", "client1"}, receivedEvents)
+	require.Equal(t, []Logger{logger1, logger2}, registry.loggers())
+	require.Nil(t, registry.eventCallbackFunc(3))
+
+	registry.unregister(1)
+	require.Nil(t, registry.eventCallbackFunc(1))
+	require.Equal(t, []Logger{logger2}, registry.loggers())
+}
+
+// Tests that events fired while a client is created are routed to it and dropped if the creation failed
+func TestCallbackRegistryEventDuringCreate(t *testing.T) {
+	registry := &callbackRegistry{clients: make(map[uintptr]*clientCallbacks)}
+
+	eventCounter := 0
+	var eventCallbackFunc EcsConfigurationEventCallbackFunc = func(event ECS_EVENT_TYPE, message string) {
+		eventCounter++
+	}
+
+	registry.beginCreate(&clientCallbacks{eventCallbackFunc: &eventCallbackFunc})
+	(*registry.eventCallbackFunc(1))(ECS_EVENT_CONFIGURATION_CHANGED, "")
+	registry.endCreate(1, true)
+	(*registry.eventCallbackFunc(1))(ECS_EVENT_CONFIGURATION_CHANGED, "")
+	require.Equal(t, 2, eventCounter)
+
+	registry.beginCreate(&clientCallbacks{eventCallbackFunc: &eventCallbackFunc})
+	require.NotNil(t, registry.eventCallbackFunc(2))
+	registry.endCreate(0, false)
+	require.Nil(t, registry.eventCallbackFunc(2))
+
+	// an event of a destroyed client during the creation of another one doesn't attach the destroyed handle to the new client
+	registry.unregister(1)
+	registry.beginCreate(&clientCallbacks{eventCallbackFunc: &eventCallbackFunc})
+	require.NotNil(t, registry.eventCallbackFunc(1))
+	registry.endCreate(3, true)
+	require.Nil(t, registry.eventCallbackFunc(1))
+	require.NotNil(t, registry.eventCallbackFunc(3))
+	registry.unregister(3)
+	require.Empty(t, registry.loggers())
+}
+
+type panickingLogger struct{}
+
+func (panickingLogger *panickingLogger) Log(logLevel ECS_LOG_LEVEL, msg string) {
+	panic("logger failed")
+}
+
+// Tests that panics of callbacks are recovered and logged, even if the logger panics itself
+func TestCallbackRegistryRecoverPanic(t *testing.T) {
+	registry := &callbackRegistry{clients: make(map[uintptr]*clientCallbacks)}
+
+	logger := &recordingLogger{}
+	registry.beginCreate(&clientCallbacks{logger: logger})
+	registry.endCreate(1, true)
+
+	require.NotPanics(t, func() {
+		defer registry.recoverPanic("event callback")
+		panic("callback failed")
+	})
+	require.Len(t, logger.messages, 1)
+	require.Contains(t, logger.messages[0], "ecs event callback panicked: callback failed")
+	require.Contains(t, logger.messages[0], "goroutine")
+
+	registry.beginCreate(&clientCallbacks{logger: &panickingLogger{}})
+	registry.endCreate(2, true)
+
+	require.NotPanics(t, func() {
+		defer registry.recoverPanic("log callback")
+		registry.log(ECS_LOG_LEVEL_ERROR, "message")
+	})
+	require.Equal(t, "message", logger.messages[1])
+}
+
+// Tests that native log messages reach the loggers of all clients, and a logger shared by clients only once
+func TestCallbackRegistryLogsToAllClients(t *testing.T) {
+	registry := &callbackRegistry{clients: make(map[uintptr]*clientCallbacks)}
+
+	logger1, logger2 := &recordingLogger{}, &recordingLogger{}
+	registry.beginCreate(&clientCallbacks{logger: logger1})
+	registry.endCreate(1, true)
+	registry.beginCreate(&clientCallbacks{logger: logger2})
+	registry.endCreate(2, true)
+	registry.beginCreate(&clientCallbacks{logger: logger1})
+	registry.endCreate(3, true)
+
+	registry.log(ECS_LOG_LEVEL_INFORMATION, "message")
+	require.Equal(t, []string{"message"}, logger1.messages)
+	require.Equal(t, []string{"message"}, logger2.messages)
+}
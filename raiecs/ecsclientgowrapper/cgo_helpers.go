@@ -1,7 +1,8 @@
+//go:build cgo && !noecsnative
+
 package ecsclientgowrapper
 
 import (
-	"fmt"
 	"unsafe"
 )
 
@@ -22,7 +23,9 @@ import "C"
 //
 //export eventCallback
 func eventCallback(a C.EcsClientHandle, b C.ECS_EVENT_CODE, c *C.char) {
-	if eventCallbackFunc != nil {
+	defer nativeCallbackRegistry.recoverPanic("event callback")
+
+	if eventCallbackFunc := nativeCallbackRegistry.eventCallbackFunc(uintptr(a)); eventCallbackFunc != nil {
 		eventType := ECS_EVENT_TYPE(int(b))
 		message := C.GoString(c)
 
@@ -34,17 +37,11 @@ func eventCallback(a C.EcsClientHandle, b C.ECS_EVENT_CODE, c *C.char) {
 //
 //export logCallback
 func logCallback(a C.ECS_LOG_LEVEL, b *C.char) {
-	if logger != nil {
-		logLevel := ECS_LOG_LEVEL(int(a))
-		logMessage := C.GoString(b)
+	defer nativeCallbackRegistry.recoverPanic("log callback")
 
-		logger.Log(logLevel, logMessage)
-	}
+	nativeCallbackRegistry.log(ECS_LOG_LEVEL(int(a)), C.GoString(b))
 }
 
-var eventCallbackFunc *EcsConfigurationEventCallbackFunc
-var logger Logger
-
 // cEcsClientOptions converts the Go EcsClientOptions to C.EcsClientOptions.
 //
 // Note that the C.EcsClientOptions are allocated in the C heap and therefore
@@ -69,16 +66,7 @@ func cEcsClientOptions(clientOptions EcsClientOptions) *C.EcsClientOptions {
 		cClientOptions.x509_cert_length = C.int(0)
 	}
 
-	if clientOptions.EcsConfigurationEventCallbackFunc != nil {
-		eventCallbackFunc = clientOptions.EcsConfigurationEventCallbackFunc
-	}
-
 	cClientOptions.event_callback = (*[0]byte)(C.eventCallback)
-
-	if clientOptions.Logger != nil {
-		logger = clientOptions.Logger
-	}
-
 	cClientOptions.log_callback = (*[0]byte)(C.logCallback)
 	cClientOptions.log_level = C.ECS_LOG_LEVEL(clientOptions.LogLevel)
 
@@ -174,11 +162,18 @@ func freeCStringArray(cArray **C.char, cArrayLen C.int) {
 	}
 }
 
-// statusCodeToError maps the C.ECS_STATUS_CODE to a Go error or nil.
+// statusCodeToError maps the C.ECS_STATUS_CODE to a *StatusError or nil.
 func statusCodeToError(statusCode C.ECS_STATUS_CODE) error {
 	if statusCode == C.ECS_STATUS_SUCCESS {
 		return nil
 	}
 
-	return fmt.Errorf("ECS operation failed with status %v", statusCode)
+	statusError := &StatusError{StatusCode: ECS_STATUS_CODE(int(statusCode))}
+
+	if cMessage := C.ecs_get_last_error(); cMessage != nil {
+		statusError.Message = C.GoString(cMessage)
+		_ = C.ecs_free_str(cMessage)
+	}
+
+	return statusError
 }
@@ -1,6 +1,10 @@
+//go:build cgo && !noecsnative
+
 package ecsclientgowrapper
 
 import (
+	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -17,74 +21,17 @@ import "C"
 
 // Represents an ECS client instance.
 type EcsClient struct {
+	// mutex guards ecsClientHandle, which is freed and set to nil when the client is destroyed
+	mutex           sync.RWMutex
 	ecsClientHandle *C.EcsClientHandle
 }
 
-// Represents the function signature of callback functions that can be registered.
-type EcsConfigurationEventCallbackFunc func(ECS_EVENT_TYPE, string)
-
-// Structure representing a key-value pair for request identifiers.
-type EcsRequestIdentifier struct {
-	// The key/name of the request identifier as UTF-8 string.
-	Name string
-
-	// The value associated with the request identifier as UTF-8 strings.
-	Values []string
-}
-
-// Request identifiers, typically service level context (e.g. environment, region, etc.).
-type EcsRequestIdentifiers []EcsRequestIdentifier
-
-// Structure representing options for configuring ECS client.
-type EcsClientOptions struct {
-	// Path to default configurations.
-	DefaultConfigPath string
-
-	// Path to default groups.
-	DefaultGroupsPath string
-
-	// Default request identifiers, typically service level context (e.g. environment, region, etc.).
-	DefaultRequestIdentifiers EcsRequestIdentifiers
-
-	// X509 certificate for authentication. Should be raw byte array of X.509 in PKCS #12 format (PFX) with private key.
-	X509Cert []byte
-
-	// TenantId if using Azure AD app authentication via SN/I. If NULL defaults to Torus tenant specific to ECS client initialized environment.
-	TenantId string
-
-	// Client ID if using Azure AD app authentication via SN/I. If NULL but x509_cert is defined, plain MTLS will be used.
-	ClientId string
-
-	// Authentication environment override. Needed if GCCMod and AAD app is in Azure Government. If NULL defaults to ECS client initialized environment.
-	AuthenticationEnvironment *ECS_ENVIRONMENT_TYPE
-
-	// The method to be used as authentication for ECS Config Service requests.
-	AuthenticationMethod ECS_AUTHENTICATION_METHOD
-
-	// Callback function that should get called when the ecs configuration changes.
-	EcsConfigurationEventCallbackFunc *EcsConfigurationEventCallbackFunc
-
-	// The Logger
-	Logger Logger
-
-	// Log level for logging messages.
-	LogLevel ECS_LOG_LEVEL
-
-	// Enable A&E ExP Control Tower based flighting for Cerberus.
-	EnableExp int
-}
-
-// Logger interface
-type Logger interface {
-	Log(logLevel ECS_LOG_LEVEL, msg string)
-}
-
 // CreateEcsClient instantiates a new EcsClient.
 func CreateEcsClient(
 	environment ECS_ENVIRONMENT_TYPE,
 	client string,
 	agents []string,
-	clientOptions EcsClientOptions) (EcsClient, error) {
+	clientOptions EcsClientOptions) (*EcsClient, error) {
 	cEnvironmentType := C.ECS_ENVIRONMENT_TYPE(C.int(int(environment)))
 
 	cClient := C.CString(client)
@@ -96,18 +43,43 @@ func CreateEcsClient(
 	cClientOptions := cEcsClientOptions(clientOptions)
 	defer freeCEcsClientOptions(cClientOptions)
 
-	ecs_client_handle := (*C.EcsClientHandle)(C.malloc(C.sizeof_EcsClientHandle))
+	// the handle is zeroed, as it is read even if ecs_create_client failed without setting it
+	ecs_client_handle := (*C.EcsClientHandle)(C.calloc(1, C.sizeof_EcsClientHandle))
+
+	// the last error message of the ecsclientlib is stored per thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
 
+	nativeCallbackRegistry.beginCreate(&clientCallbacks{
+		eventCallbackFunc: clientOptions.EcsConfigurationEventCallbackFunc,
+		logger:            clientOptions.Logger,
+	})
 	status_code := C.ecs_create_client(cEnvironmentType, cClient, cAgents, cAgentsLen, cClientOptions, ecs_client_handle)
+	nativeCallbackRegistry.endCreate(uintptr(*ecs_client_handle), status_code == C.ECS_STATUS_SUCCESS)
 
-	return EcsClient{ecsClientHandle: ecs_client_handle}, statusCodeToError(status_code)
+	if status_code != C.ECS_STATUS_SUCCESS {
+		C.free(unsafe.Pointer(ecs_client_handle))
+		return nil, statusCodeToError(status_code)
+	}
+
+	return &EcsClient{ecsClientHandle: ecs_client_handle}, nil
 }
 
 // GetConfig fetches the ECS config.
-func (ecsClient EcsClient) GetConfig(ecsRequestIdentifiers EcsRequestIdentifiers) (string, error) {
+func (ecsClient *EcsClient) GetConfig(ecsRequestIdentifiers EcsRequestIdentifiers) (string, error) {
+	ecsClient.mutex.RLock()
+	defer ecsClient.mutex.RUnlock()
+
+	if ecsClient.ecsClientHandle == nil {
+		return "", ErrClientDestroyed
+	}
+
 	crequestIdentifiers, cRequestIdentifiersLen := cEcsRequestIdentifiers(ecsRequestIdentifiers)
 	defer freeCEcsRequestIdentifiers(crequestIdentifiers, cRequestIdentifiersLen)
 
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
 	var out_config *C.char
 	defer func() {
 		_ = C.ecs_free_str(out_config)
@@ -119,7 +91,25 @@ func (ecsClient EcsClient) GetConfig(ecsRequestIdentifiers EcsRequestIdentifiers
 	return go_out_config, statusCodeToError(status_code)
 }
 
-// DestroyClient destroys the EcsClient instance.
-func (ecsClient EcsClient) DestroyClient() error {
-	return statusCodeToError(C.ecs_destroy_client(*ecsClient.ecsClientHandle))
+// DestroyClient destroys the EcsClient instance and frees its handle. Its callbacks are unregistered and won't be called anymore.
+// It waits for in-flight GetConfig calls to finish.
+func (ecsClient *EcsClient) DestroyClient() error {
+	ecsClient.mutex.Lock()
+	defer ecsClient.mutex.Unlock()
+
+	if ecsClient.ecsClientHandle == nil {
+		return ErrClientDestroyed
+	}
+
+	nativeCallbackRegistry.unregister(uintptr(*ecsClient.ecsClientHandle))
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	status_code := C.ecs_destroy_client(*ecsClient.ecsClientHandle)
+
+	C.free(unsafe.Pointer(ecsClient.ecsClientHandle))
+	ecsClient.ecsClientHandle = nil
+
+	return statusCodeToError(status_code)
 }
@@ -0,0 +1,28 @@
+//go:build !cgo || noecsnative
+
+package ecsclientgowrapper
+
+// This file replaces the cgo bindings of the ecsclientlib when building without cgo or with the noecsnative build tag,
+// so packages that only use the pure Go parts of the ECS go client don't require the native library.
+
+// Represents an ECS client instance. Without the native library no instance can be created.
+type EcsClient struct{}
+
+// CreateEcsClient returns ErrNativeUnavailable, as the native library is not part of this build.
+func CreateEcsClient(
+	environment ECS_ENVIRONMENT_TYPE,
+	client string,
+	agents []string,
+	clientOptions EcsClientOptions) (*EcsClient, error) {
+	return nil, ErrNativeUnavailable
+}
+
+// GetConfig returns ErrNativeUnavailable, as the native library is not part of this build.
+func (ecsClient *EcsClient) GetConfig(ecsRequestIdentifiers EcsRequestIdentifiers) (string, error) {
+	return "", ErrNativeUnavailable
+}
+
+// DestroyClient returns ErrNativeUnavailable, as the native library is not part of this build.
+func (ecsClient *EcsClient) DestroyClient() error {
+	return ErrNativeUnavailable
+}
@@ -27,8 +27,29 @@ const (
 
 	// Government Cloud Computing Moderate/Low environment.
 	ECS_ENVIRONMENT_TYPE_GCCMOD ECS_ENVIRONMENT_TYPE = 8
+
+	// Canary environment.
+	ECS_ENVIRONMENT_TYPE_CANARY ECS_ENVIRONMENT_TYPE = 9
 )
 
+// IsValid returns true if the environment is one of the known ECS environments.
+func (environment ECS_ENVIRONMENT_TYPE) IsValid() bool {
+	switch environment {
+	case ECS_ENVIRONMENT_TYPE_INTEGRATION,
+		ECS_ENVIRONMENT_TYPE_PRODUCTION,
+		ECS_ENVIRONMENT_TYPE_DOD,
+		ECS_ENVIRONMENT_TYPE_GCCH,
+		ECS_ENVIRONMENT_TYPE_AG08,
+		ECS_ENVIRONMENT_TYPE_AG09,
+		ECS_ENVIRONMENT_TYPE_MOONCAKE,
+		ECS_ENVIRONMENT_TYPE_GCCMOD,
+		ECS_ENVIRONMENT_TYPE_CANARY:
+		return true
+	}
+
+	return false
+}
+
 // Enumeration representing the event codes returned by the ECS API functions.
 type ECS_EVENT_TYPE int
 
@@ -43,6 +64,20 @@ const (
 	ECS_EVENT_CONFIGURATION_ERROR
 )
 
+// String returns the name of the event type as used in metric labels and span attributes, or "unknown" if it is none of the known types.
+func (eventType ECS_EVENT_TYPE) String() string {
+	switch eventType {
+	case ECS_EVENT_CONFIGURATION_CHANGED:
+		return "changed"
+	case ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE:
+		return "changed_from_cache"
+	case ECS_EVENT_CONFIGURATION_ERROR:
+		return "error"
+	}
+
+	return "unknown"
+}
+
 // Enumeration representing the log levels used by the ECS API functions.
 type ECS_LOG_LEVEL int
 
@@ -79,3 +114,14 @@ const (
 	// User Assigned Managed Identity. See also https://learn.microsoft.com/en-us/azure/active-directory/managed-identities-azure-resources/overview#managed-identity-types .
 	ECS_AUTHENTICATION_METHOD_USERASSIGNEDMANAGEDIDENTITY ECS_AUTHENTICATION_METHOD = 4
 )
+
+// Enumeration representing the status codes returned by the ECS API functions.
+type ECS_STATUS_CODE int
+
+const (
+	// Success status code.
+	ECS_STATUS_SUCCESS ECS_STATUS_CODE = 0
+
+	// Undefined error status code.
+	ECS_STATUS_ERROR_UNDEFINED ECS_STATUS_CODE = -1
+)
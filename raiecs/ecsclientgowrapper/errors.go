@@ -0,0 +1,41 @@
+package ecsclientgowrapper
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrOperationFailed is matched by all errors that are returned for a failed ECS API call.
+	ErrOperationFailed = errors.New("ECS operation failed")
+
+	// ErrClientDestroyed is returned by calls on an EcsClient that has already been destroyed.
+	ErrClientDestroyed = errors.New("ecs client has been destroyed")
+
+	// ErrNativeUnavailable is returned when the package was built without cgo or with the noecsnative build tag,
+	// so the ecsclientlib can't be used.
+	ErrNativeUnavailable = errors.New("the native ecs client library is not available in this build")
+)
+
+// StatusError is returned when an ECS API function returns a status code other than ECS_STATUS_SUCCESS.
+type StatusError struct {
+	// The status code returned by the ECS API function.
+	StatusCode ECS_STATUS_CODE
+
+	// The last error message reported by the ecsclientlib. May be empty.
+	Message string
+}
+
+// Error returns the error message including the status code.
+func (statusError *StatusError) Error() string {
+	if statusError.Message == "" {
+		return fmt.Sprintf("ECS operation failed with status %v", statusError.StatusCode)
+	}
+
+	return fmt.Sprintf("ECS operation failed with status %v: %v", statusError.StatusCode, statusError.Message)
+}
+
+// Is reports whether target is ErrOperationFailed.
+func (statusError *StatusError) Is(target error) bool {
+	return target == ErrOperationFailed
+}
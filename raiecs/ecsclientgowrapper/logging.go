@@ -0,0 +1,206 @@
+package ecsclientgowrapper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SlogLevelCritical is the slog.Level that ECS_LOG_LEVEL_CRITICAL is mapped to.
+const SlogLevelCritical = slog.LevelError + 4
+
+// StructuredLogger is implemented by Loggers that accept structured attributes in addition to the message.
+type StructuredLogger interface {
+	Logger
+
+	// LogAttrs logs msg with the given attributes.
+	LogAttrs(logLevel ECS_LOG_LEVEL, msg string, attrs ...slog.Attr)
+}
+
+// LogAttrs logs msg with attrs to logger. If logger is not a StructuredLogger, the attributes are appended to the message as key=value pairs.
+func LogAttrs(logger Logger, logLevel ECS_LOG_LEVEL, msg string, attrs ...slog.Attr) {
+	if structuredLogger, ok := logger.(StructuredLogger); ok {
+		structuredLogger.LogAttrs(logLevel, msg, attrs...)
+		return
+	}
+
+	logger.Log(logLevel, formatAttrs(msg, attrs))
+}
+
+// SlogLevel maps an ECS_LOG_LEVEL to the corresponding slog.Level.
+func SlogLevel(logLevel ECS_LOG_LEVEL) slog.Level {
+	switch {
+	case logLevel >= ECS_LOG_LEVEL_CRITICAL:
+		return SlogLevelCritical
+	case logLevel >= ECS_LOG_LEVEL_ERROR:
+		return slog.LevelError
+	case logLevel >= ECS_LOG_LEVEL_WARNING:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// EcsLogLevel maps a slog.Level to the corresponding ECS_LOG_LEVEL. slog.LevelDebug is mapped to ECS_LOG_LEVEL_INFORMATION,
+// since the ecsclientlib has no lower log level.
+func EcsLogLevel(level slog.Level) ECS_LOG_LEVEL {
+	switch {
+	case level >= SlogLevelCritical:
+		return ECS_LOG_LEVEL_CRITICAL
+	case level >= slog.LevelError:
+		return ECS_LOG_LEVEL_ERROR
+	case level >= slog.LevelWarn:
+		return ECS_LOG_LEVEL_WARNING
+	default:
+		return ECS_LOG_LEVEL_INFORMATION
+	}
+}
+
+// slogLogger is a StructuredLogger that writes to a slog.Handler.
+type slogLogger struct {
+	handler slog.Handler
+}
+
+// NewSlogLogger returns a Logger that writes the log messages to handler, with the log levels mapped by SlogLevel.
+func NewSlogLogger(handler slog.Handler) StructuredLogger {
+	return &slogLogger{handler: handler}
+}
+
+// Log writes msg to the slog.Handler.
+func (slogLogger *slogLogger) Log(logLevel ECS_LOG_LEVEL, msg string) {
+	slogLogger.LogAttrs(logLevel, msg)
+}
+
+// LogAttrs writes msg with attrs to the slog.Handler, unless the handler is disabled for the log level.
+func (slogLogger *slogLogger) LogAttrs(logLevel ECS_LOG_LEVEL, msg string, attrs ...slog.Attr) {
+	if logLevel == ECS_LOG_LEVEL_NONE {
+		return
+	}
+
+	ctx := context.Background()
+	level := SlogLevel(logLevel)
+	if !slogLogger.handler.Enabled(ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), level, msg, 0)
+	record.AddAttrs(attrs...)
+	_ = slogLogger.handler.Handle(ctx, record)
+}
+
+// loggerHandler is a slog.Handler that writes to a Logger.
+type loggerHandler struct {
+	logger Logger
+	attrs  []slog.Attr
+	prefix string
+}
+
+// NewSlogHandler returns a slog.Handler that writes the records to logger, with the log levels mapped by EcsLogLevel.
+// This allows to use a Logger with libraries that log through log/slog.
+func NewSlogHandler(logger Logger) slog.Handler {
+	return &loggerHandler{logger: logger}
+}
+
+// Enabled reports true for all levels, the filtering is left to the Logger.
+func (loggerHandler *loggerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// Handle writes the record to the Logger.
+func (loggerHandler *loggerHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(loggerHandler.attrs)+record.NumAttrs())
+	attrs = append(attrs, loggerHandler.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, loggerHandler.qualify(attr))
+		return true
+	})
+
+	LogAttrs(loggerHandler.logger, EcsLogLevel(record.Level), record.Message, attrs...)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (loggerHandler *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *loggerHandler
+	handler.attrs = make([]slog.Attr, 0, len(loggerHandler.attrs)+len(attrs))
+	handler.attrs = append(handler.attrs, loggerHandler.attrs...)
+	for _, attr := range attrs {
+		handler.attrs = append(handler.attrs, loggerHandler.qualify(attr))
+	}
+
+	return &handler
+}
+
+// WithGroup returns a handler that prefixes the keys of the following attributes with name.
+func (loggerHandler *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return loggerHandler
+	}
+
+	handler := *loggerHandler
+	handler.prefix = loggerHandler.prefix + name + "."
+	return &handler
+}
+
+// qualify prefixes the key of attr with the open groups.
+func (loggerHandler *loggerHandler) qualify(attr slog.Attr) slog.Attr {
+	if loggerHandler.prefix == "" {
+		return attr
+	}
+
+	return slog.Attr{Key: loggerHandler.prefix + attr.Key, Value: attr.Value}
+}
+
+// levelLogger drops the log messages below a minimum log level.
+type levelLogger struct {
+	logger   Logger
+	minLevel ECS_LOG_LEVEL
+}
+
+// NewLevelLogger returns a Logger that only forwards messages with at least minLevel to logger, like the ecsclientlib does for its
+// LogLevel option. ECS_LOG_LEVEL_NONE drops all messages.
+func NewLevelLogger(logger Logger, minLevel ECS_LOG_LEVEL) StructuredLogger {
+	return &levelLogger{logger: logger, minLevel: minLevel}
+}
+
+// Log forwards msg to the Logger if logLevel is enabled.
+func (levelLogger *levelLogger) Log(logLevel ECS_LOG_LEVEL, msg string) {
+	if levelLogger.enabled(logLevel) {
+		levelLogger.logger.Log(logLevel, msg)
+	}
+}
+
+// LogAttrs forwards msg with attrs to the Logger if logLevel is enabled.
+func (levelLogger *levelLogger) LogAttrs(logLevel ECS_LOG_LEVEL, msg string, attrs ...slog.Attr) {
+	if levelLogger.enabled(logLevel) {
+		LogAttrs(levelLogger.logger, logLevel, msg, attrs...)
+	}
+}
+
+// enabled reports whether messages with logLevel are forwarded.
+func (levelLogger *levelLogger) enabled(logLevel ECS_LOG_LEVEL) bool {
+	return levelLogger.minLevel != ECS_LOG_LEVEL_NONE && logLevel != ECS_LOG_LEVEL_NONE && logLevel >= levelLogger.minLevel
+}
+
+// formatAttrs appends attrs to msg as key=value pairs. Single-line values with spaces, '=' or quotes are quoted, multi-line values like
+// stack traces are kept as they are.
+func formatAttrs(msg string, attrs []slog.Attr) string {
+	if len(attrs) == 0 {
+		return msg
+	}
+
+	var builder strings.Builder
+	builder.WriteString(msg)
+	for _, attr := range attrs {
+		value := attr.Value.Resolve().String()
+		if !strings.Contains(value, "\n") && strings.ContainsAny(value, " =\"") {
+			value = fmt.Sprintf("%q", value)
+		}
+
+		fmt.Fprintf(&builder, " %v=%v", attr.Key, value)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,51 @@
+package ecsclientgowrapper
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the log levels are mapped between ECS_LOG_LEVEL and slog.Level in both directions
+func TestLogLevelMapping(t *testing.T) {
+	for _, logLevel := range []ECS_LOG_LEVEL{ECS_LOG_LEVEL_INFORMATION, ECS_LOG_LEVEL_WARNING, ECS_LOG_LEVEL_ERROR, ECS_LOG_LEVEL_CRITICAL} {
+		require.Equal(t, logLevel, EcsLogLevel(SlogLevel(logLevel)))
+	}
+
+	require.Equal(t, slog.LevelWarn, SlogLevel(ECS_LOG_LEVEL_WARNING))
+	require.Equal(t, SlogLevelCritical, SlogLevel(ECS_LOG_LEVEL_CRITICAL))
+	require.Equal(t, ECS_LOG_LEVEL_INFORMATION, EcsLogLevel(slog.LevelDebug))
+}
+
+// Tests that the slog adapters pass the messages and attributes through in both directions
+func TestSlogAdapters(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewSlogLogger(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	logger.Log(ECS_LOG_LEVEL_INFORMATION, "dropped by the handler")
+	LogAttrs(logger, ECS_LOG_LEVEL_ERROR, "update failed", slog.String("option", "ConfigName"))
+	require.NotContains(t, buffer.String(), "dropped by the handler")
+	require.Contains(t, buffer.String(), `level=ERROR msg="update failed" option=ConfigName`)
+
+	recorder := &recordingLogger{}
+	slogger := slog.New(NewSlogHandler(recorder)).With("project_team", "TestProjectTeam").WithGroup("config")
+	slogger.Info("received", "checksum", "abc", "value", "a b")
+	require.Equal(t, []string{`received project_team=TestProjectTeam config.checksum=abc config.value="a b"`}, recorder.messages)
+}
+
+// Tests that the level logger drops the messages below its minimum level and all messages for ECS_LOG_LEVEL_NONE
+func TestLevelLogger(t *testing.T) {
+	recorder := &recordingLogger{}
+	logger := NewLevelLogger(recorder, ECS_LOG_LEVEL_WARNING)
+
+	logger.Log(ECS_LOG_LEVEL_INFORMATION, "information")
+	logger.Log(ECS_LOG_LEVEL_WARNING, "warning")
+	LogAttrs(logger, ECS_LOG_LEVEL_ERROR, "error", slog.Int("attempt", 2))
+	require.Equal(t, []string{"warning", "error attempt=2"}, recorder.messages)
+
+	recorder = &recordingLogger{}
+	NewLevelLogger(recorder, ECS_LOG_LEVEL_NONE).Log(ECS_LOG_LEVEL_CRITICAL, "critical")
+	require.Empty(t, recorder.messages)
+}
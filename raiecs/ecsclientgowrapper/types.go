@@ -0,0 +1,61 @@
+package ecsclientgowrapper
+
+// Represents the function signature of callback functions that can be registered.
+type EcsConfigurationEventCallbackFunc func(ECS_EVENT_TYPE, string)
+
+// Structure representing a key-value pair for request identifiers.
+type EcsRequestIdentifier struct {
+	// The key/name of the request identifier as UTF-8 string.
+	Name string
+
+	// The value associated with the request identifier as UTF-8 strings.
+	Values []string
+}
+
+// Request identifiers, typically service level context (e.g. environment, region, etc.).
+type EcsRequestIdentifiers []EcsRequestIdentifier
+
+// Structure representing options for configuring ECS client.
+type EcsClientOptions struct {
+	// Path to default configurations.
+	DefaultConfigPath string
+
+	// Path to default groups.
+	DefaultGroupsPath string
+
+	// Default request identifiers, typically service level context (e.g. environment, region, etc.).
+	DefaultRequestIdentifiers EcsRequestIdentifiers
+
+	// X509 certificate for authentication. Should be raw byte array of X.509 in PKCS #12 format (PFX) with private key.
+	X509Cert []byte
+
+	// TenantId if using Azure AD app authentication via SN/I. If NULL defaults to Torus tenant specific to ECS client initialized environment.
+	TenantId string
+
+	// Client ID if using Azure AD app authentication via SN/I. If NULL but x509_cert is defined, plain MTLS will be used.
+	ClientId string
+
+	// Authentication environment override. Needed if GCCMod and AAD app is in Azure Government. If NULL defaults to ECS client initialized environment.
+	AuthenticationEnvironment *ECS_ENVIRONMENT_TYPE
+
+	// The method to be used as authentication for ECS Config Service requests.
+	AuthenticationMethod ECS_AUTHENTICATION_METHOD
+
+	// Callback function that should get called when the ecs configuration changes.
+	EcsConfigurationEventCallbackFunc *EcsConfigurationEventCallbackFunc
+
+	// The Logger. The ecs C library doesn't tell which client a log message belongs to, so the log messages of the ecs C library
+	// are passed to the loggers of all clients in the process.
+	Logger Logger
+
+	// Log level for logging messages.
+	LogLevel ECS_LOG_LEVEL
+
+	// Enable A&E ExP Control Tower based flighting for Cerberus.
+	EnableExp int
+}
+
+// Logger interface
+type Logger interface {
+	Log(logLevel ECS_LOG_LEVEL, msg string)
+}
@@ -0,0 +1,151 @@
+// Package ecsprometheus exports the metrics of an ecsgoclient.EcsClient to Prometheus.
+package ecsprometheus
+
+import (
+	"sync"
+	"time"
+
+	ecsgoclient "github.com/raiecs"
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is an ecsgoclient.MetricsRecorder that exposes the recorded metrics as prometheus.Collector.
+// Pass it to the EcsClient with ecsgoclient.WithMetricsRecorder and register it on a prometheus.Registerer.
+type Collector struct {
+	fetchDuration   *prometheus.HistogramVec
+	fetchErrors     *prometheus.CounterVec
+	events          *prometheus.CounterVec
+	updates         *prometheus.CounterVec
+	checksumChanges *prometheus.CounterVec
+
+	secondsSinceLastSuccess *prometheus.Desc
+
+	// mutex guards lastSuccess
+	mutex       sync.Mutex
+	lastSuccess map[optionKey]time.Time
+
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// optionKey identifies an options monitor
+type optionKey struct {
+	projectTeam string
+	optionName  string
+}
+
+var _ ecsgoclient.MetricsRecorder = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector whose metrics are prefixed with namespace, e.g. "myservice"
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "ecs",
+			Name:      "fetch_duration_seconds",
+			Help:      "Duration of the ECS config fetches.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+		fetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ecs",
+			Name:      "fetch_errors_total",
+			Help:      "Number of failed ECS config fetches by error category.",
+		}, []string{"category"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ecs",
+			Name:      "events_total",
+			Help:      "Number of events reported by the ecs C library by event type.",
+		}, []string{"type"}),
+		updates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ecs",
+			Name:      "option_updates_total",
+			Help:      "Number of evaluated options updates by result and, for rejected updates, error category.",
+		}, []string{"project_team", "option", "result", "category"}),
+		checksumChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ecs",
+			Name:      "option_checksum_changes_total",
+			Help:      "Number of accepted options updates that changed the config.",
+		}, []string{"project_team", "option"}),
+		secondsSinceLastSuccess: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ecs", "option_seconds_since_last_success"),
+			"Seconds since the last accepted options update.",
+			[]string{"project_team", "option"}, nil),
+		lastSuccess: make(map[optionKey]time.Time),
+		now:         time.Now,
+	}
+}
+
+// ObserveFetch records the duration of a config fetch and its error category if it failed
+func (collector *Collector) ObserveFetch(duration time.Duration, err error) {
+	if err != nil {
+		collector.fetchDuration.WithLabelValues("error").Observe(duration.Seconds())
+		collector.fetchErrors.WithLabelValues(ecsgoclient.ErrorCategory(err)).Inc()
+		return
+	}
+
+	collector.fetchDuration.WithLabelValues("success").Observe(duration.Seconds())
+}
+
+// ObserveEvent counts the events of the ecs C library by type
+func (collector *Collector) ObserveEvent(eventType ecsclientgowrapper.ECS_EVENT_TYPE) {
+	collector.events.WithLabelValues(eventType.String()).Inc()
+}
+
+// ObserveUpdate counts the accepted and rejected options updates and the checksum changes
+func (collector *Collector) ObserveUpdate(projectTeam string, optionName string, changed bool, err error) {
+	if err != nil {
+		collector.updates.WithLabelValues(projectTeam, optionName, "rejected", ecsgoclient.ErrorCategory(err)).Inc()
+		return
+	}
+
+	collector.updates.WithLabelValues(projectTeam, optionName, "accepted", "").Inc()
+	if changed {
+		collector.checksumChanges.WithLabelValues(projectTeam, optionName).Inc()
+	}
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.lastSuccess[optionKey{projectTeam: projectTeam, optionName: optionName}] = collector.now()
+}
+
+// ObserveRemove forgets the last success of the removed option, so no staleness is reported for options that are no longer monitored.
+// The counters are kept, as they still count the updates of the option before it was removed.
+func (collector *Collector) ObserveRemove(projectTeam string, optionName string) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	delete(collector.lastSuccess, optionKey{projectTeam: projectTeam, optionName: optionName})
+}
+
+// Describe sends the descriptors of all metrics of the Collector
+func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	collector.fetchDuration.Describe(ch)
+	collector.fetchErrors.Describe(ch)
+	collector.events.Describe(ch)
+	collector.updates.Describe(ch)
+	collector.checksumChanges.Describe(ch)
+	ch <- collector.secondsSinceLastSuccess
+}
+
+// Collect sends the current values of all metrics of the Collector
+func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	collector.fetchDuration.Collect(ch)
+	collector.fetchErrors.Collect(ch)
+	collector.events.Collect(ch)
+	collector.updates.Collect(ch)
+	collector.checksumChanges.Collect(ch)
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	now := collector.now()
+	for key, lastSuccess := range collector.lastSuccess {
+		ch <- prometheus.MustNewConstMetric(collector.secondsSinceLastSuccess, prometheus.GaugeValue, now.Sub(lastSuccess).Seconds(), key.projectTeam, key.optionName)
+	}
+}
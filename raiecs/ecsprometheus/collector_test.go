@@ -0,0 +1,105 @@
+package ecsprometheus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ecsgoclient "github.com/raiecs"
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type staticConfigGetter struct {
+	config string
+	err    error
+}
+
+func (staticConfigGetter *staticConfigGetter) GetConfig(ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error) {
+	return staticConfigGetter.config, staticConfigGetter.err
+}
+
+type TestConfig struct {
+	TestProperty string `json:"TestProperty"`
+}
+
+type NoopLogger struct{}
+
+func (ecsLogger *NoopLogger) Log(logLevel ecsclientgowrapper.ECS_LOG_LEVEL, msg string) {}
+
+// Tests that the metrics of the EcsClient are recorded and exposed to a prometheus registry
+func TestCollector(t *testing.T) {
+	ecsConfigGetter := &staticConfigGetter{config: `{"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue1"}}}`}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	collector := NewCollector("test")
+	collector.now = func() time.Time { return now }
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	ecsClientInstance := ecsgoclient.NewEcsClientFromConfigGetter(ecsConfigGetter, &NoopLogger{}, ecsgoclient.WithMetricsRecorder(collector))
+
+	_, err := ecsgoclient.AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	_, err = ecsgoclient.AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "MissingConfigName")
+	require.ErrorIs(t, err, ecsgoclient.ErrOptionNotFound)
+
+	ecsConfigGetter.err = ecsclientgowrapper.ErrOperationFailed
+	_, err = ecsClientInstance.GetConfigContext(context.Background(), ecsclientgowrapper.EcsRequestIdentifiers{})
+	require.Error(t, err)
+
+	collector.ObserveEvent(ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE)
+	collector.ObserveUpdate("TestProjectTeam", "ConfigName", false, nil)
+	now = now.Add(90 * time.Second)
+
+	expected := `
+# HELP test_ecs_events_total Number of events reported by the ecs C library by event type.
+# TYPE test_ecs_events_total counter
+test_ecs_events_total{type="changed_from_cache"} 1
+# HELP test_ecs_fetch_errors_total Number of failed ECS config fetches by error category.
+# TYPE test_ecs_fetch_errors_total counter
+test_ecs_fetch_errors_total{category="native"} 1
+# HELP test_ecs_option_checksum_changes_total Number of accepted options updates that changed the config.
+# TYPE test_ecs_option_checksum_changes_total counter
+test_ecs_option_checksum_changes_total{option="ConfigName",project_team="TestProjectTeam"} 1
+# HELP test_ecs_option_seconds_since_last_success Seconds since the last accepted options update.
+# TYPE test_ecs_option_seconds_since_last_success gauge
+test_ecs_option_seconds_since_last_success{option="ConfigName",project_team="TestProjectTeam"} 90
+# HELP test_ecs_option_updates_total Number of evaluated options updates by result and, for rejected updates, error category.
+# TYPE test_ecs_option_updates_total counter
+test_ecs_option_updates_total{category="",option="ConfigName",project_team="TestProjectTeam",result="accepted"} 2
+test_ecs_option_updates_total{category="option_not_found",option="MissingConfigName",project_team="TestProjectTeam",result="rejected"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"test_ecs_events_total", "test_ecs_fetch_errors_total", "test_ecs_option_checksum_changes_total",
+		"test_ecs_option_seconds_since_last_success", "test_ecs_option_updates_total"))
+
+	require.Equal(t, 2, testutil.CollectAndCount(collector, "test_ecs_fetch_duration_seconds"))
+}
+
+// Tests that the seconds since the last success are no longer reported once the options monitor has been removed
+func TestCollectorRemovedMonitor(t *testing.T) {
+	ecsConfigGetter := &staticConfigGetter{config: `{"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue1"}}}`}
+
+	collector := NewCollector("test")
+	ecsClientInstance := ecsgoclient.NewEcsClientFromConfigGetter(ecsConfigGetter, &NoopLogger{}, ecsgoclient.WithMetricsRecorder(collector))
+
+	monitor, err := ecsgoclient.AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	otherMonitor, err := ecsgoclient.AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, 1, testutil.CollectAndCount(collector, "test_ecs_option_seconds_since_last_success"))
+
+	// the option is still monitored by the other monitor
+	monitor.Remove()
+	require.Equal(t, 1, testutil.CollectAndCount(collector, "test_ecs_option_seconds_since_last_success"))
+
+	otherMonitor.Remove()
+	require.Equal(t, 0, testutil.CollectAndCount(collector, "test_ecs_option_seconds_since_last_success"))
+}
@@ -0,0 +1,155 @@
+package ecsgoclient
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// Sentinel errors that classify the errors returned by the EcsClient. Use errors.Is to check for them.
+var (
+	// ErrClientClosed is returned by calls on an EcsClient that has been closed
+	ErrClientClosed = errors.New("ecs client is closed")
+
+	// ErrInvalidClientOptions is returned if the EcsClientOptions are invalid
+	ErrInvalidClientOptions = errors.New("invalid ecs client options")
+
+	// ErrClientCreationFailed is returned if the native ecs client could not be created
+	ErrClientCreationFailed = errors.New("failed to create ecs client")
+
+	// ErrFetchFailed is returned if the config could not be fetched from ECS
+	ErrFetchFailed = errors.New("failed to fetch ecs config")
+
+	// ErrInvalidConfig is returned if the config received from ECS could not be parsed
+	ErrInvalidConfig = errors.New("failed to parse ecs config")
+
+	// ErrProjectTeamNotFound is returned if the monitored project team is missing in the config received from ECS
+	ErrProjectTeamNotFound = errors.New("project team not found in ecs config")
+
+	// ErrOptionNotFound is returned if the monitored option is missing in the config received from ECS
+	ErrOptionNotFound = errors.New("option not found in ecs config")
+
+	// ErrValidationFailed is returned if the received options were rejected by OnOptionsUpdateReceived
+	ErrValidationFailed = errors.New("options update rejected")
+
+	// ErrPanicked is returned if OnOptionsUpdateReceived or an update callback panicked
+	ErrPanicked = errors.New("options update panicked")
+
+	// ErrMonitorAlreadyRegistered is returned if an options monitor is added twice for the same options
+	ErrMonitorAlreadyRegistered = errors.New("there is already an options monitor registered for the same options")
+
+	// ErrMonitorNotFound is returned if no options monitor is registered for the provided options
+	ErrMonitorNotFound = errors.New("no options monitor registered for the provided options")
+)
+
+// EcsError is the structured error returned by the EcsClient for failed fetches and options updates.
+// It matches its Kind and its wrapped Err with errors.Is and errors.As.
+type EcsError struct {
+	// One of the sentinel errors, e.g. ErrFetchFailed
+	Kind error
+
+	// The status code of the ecs C library call. ECS_STATUS_SUCCESS if the error did not come from the ecs C library.
+	StatusCode ecsclientgowrapper.ECS_STATUS_CODE
+
+	// The project team of the options monitor, if any
+	ProjectTeam string
+
+	// The option name of the options monitor, if any
+	OptionName string
+
+	// The underlying cause, may be nil
+	Err error
+}
+
+// newEcsError creates an EcsError of kind for the given options monitor and cause, and takes over the status code of the cause.
+func newEcsError(kind error, projectTeam string, optionName string, err error) *EcsError {
+	ecsError := &EcsError{
+		Kind:        kind,
+		ProjectTeam: projectTeam,
+		OptionName:  optionName,
+		Err:         err,
+	}
+
+	var statusError *ecsclientgowrapper.StatusError
+	if errors.As(err, &statusError) {
+		ecsError.StatusCode = statusError.StatusCode
+	}
+
+	return ecsError
+}
+
+// Error returns the error message including the options monitor and the cause
+func (ecsError *EcsError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(ecsError.Kind.Error())
+
+	if ecsError.ProjectTeam != "" {
+		sb.WriteString(fmt.Sprintf(" (projectTeam '%v'", ecsError.ProjectTeam))
+		if ecsError.OptionName != "" {
+			sb.WriteString(fmt.Sprintf(", option '%v'", ecsError.OptionName))
+		}
+		sb.WriteString(")")
+	}
+
+	if ecsError.Err != nil {
+		sb.WriteString(": ")
+		sb.WriteString(ecsError.Err.Error())
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the Kind and the cause of the error
+func (ecsError *EcsError) Unwrap() []error {
+	if ecsError.Err == nil {
+		return []error{ecsError.Kind}
+	}
+
+	return []error{ecsError.Kind, ecsError.Err}
+}
+
+// PanicError is the error a recovered panic of OnOptionsUpdateReceived or an update callback is converted into. It matches ErrPanicked.
+type PanicError struct {
+	// The value that was passed to panic
+	Value any
+
+	// The stack trace of the panicking goroutine
+	Stack []byte
+}
+
+// newPanicError creates a PanicError for the recovered value with the current stack trace. It must be called in the deferred func that recovered.
+func newPanicError(recovered any) *PanicError {
+	return &PanicError{Value: recovered, Stack: debug.Stack()}
+}
+
+// Error returns the panic value
+func (panicError *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", panicError.Value)
+}
+
+// Is reports whether target is ErrPanicked
+func (panicError *PanicError) Is(target error) bool {
+	return target == ErrPanicked
+}
+
+// Unwrap returns the panic value if it is an error
+func (panicError *PanicError) Unwrap() error {
+	err, _ := panicError.Value.(error)
+	return err
+}
+
+// errorLogAttrs returns the log attributes of err, including the stack trace if err was caused by a panic
+func errorLogAttrs(err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String(LogKeyError, err.Error())}
+
+	var panicError *PanicError
+	if errors.As(err, &panicError) {
+		attrs = append(attrs, slog.String(LogKeyStack, string(panicError.Stack)))
+	}
+
+	return attrs
+}
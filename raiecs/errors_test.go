@@ -0,0 +1,181 @@
+package ecsgoclient
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the errors of failed initial config loads can be distinguished with errors.Is and errors.As
+func TestEcsGoClientErrorKinds(t *testing.T) {
+	statusError := &ecsclientgowrapper.StatusError{StatusCode: ecsclientgowrapper.ECS_STATUS_ERROR_UNDEFINED, Message: "some error"}
+
+	testCases := []struct {
+		name        string
+		config      string
+		fetchError  error
+		optionName  string
+		expectedErr error
+	}{
+		{name: "fetch failed", fetchError: statusError, optionName: "ConfigName", expectedErr: ErrFetchFailed},
+		{name: "invalid json", config: "{", optionName: "ConfigName", expectedErr: ErrInvalidConfig},
+		{name: "project team missing", config: `{"OtherProjectTeam": {}}`, optionName: "ConfigName", expectedErr: ErrProjectTeamNotFound},
+		{name: "option missing", config: validConfigUpdate1, optionName: "OtherConfigName", expectedErr: ErrOptionNotFound},
+		{name: "validation failed", config: invalidConfigUpdate, optionName: "ConfigName", expectedErr: ErrValidationFailed},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			ecsConfigGetter := mockConfigGetter{}
+			ecsConfigGetter.On("GetConfig", mock.Anything).Return(testCase.config, testCase.fetchError)
+
+			ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+			_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(&TestConfig{}, "TestProjectTeam", testCase.optionName)
+			require.ErrorIs(t, err, testCase.expectedErr)
+
+			var ecsError *EcsError
+			require.ErrorAs(t, err, &ecsError)
+			require.Equal(t, testCase.expectedErr, ecsError.Kind)
+
+			if testCase.fetchError != nil {
+				require.ErrorIs(t, err, ecsclientgowrapper.ErrOperationFailed)
+				require.Equal(t, ecsclientgowrapper.ECS_STATUS_ERROR_UNDEFINED, ecsError.StatusCode)
+			} else {
+				require.Equal(t, "TestProjectTeam", ecsError.ProjectTeam)
+				require.Equal(t, testCase.optionName, ecsError.OptionName)
+			}
+		})
+	}
+}
+
+// Tests that decoding failures of a typed monitor are reported as invalid config, not as validation failure
+func TestTypedMonitorDecodingErrorKind(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestProperty": 1}}}`, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.False(t, errors.Is(err, ErrValidationFailed))
+}
+
+// Tests that registering the same options twice and callbacks for unknown options return sentinel errors
+func TestEcsGoClientMonitorRegistrationErrors(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	testConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	_, err = ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, ErrMonitorAlreadyRegistered)
+
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(&TestConfig{}, func(error) {})
+	require.ErrorIs(t, err, ErrMonitorNotFound)
+	require.ErrorIs(t, ecsClientInstance.RemoveOptionsMonitor(&TestConfig{}), ErrMonitorNotFound)
+}
+
+type panickingConfig struct {
+	TestConfig
+}
+
+func (panickingConfig *panickingConfig) OnOptionsUpdateReceived(bytes []byte) error {
+	if err := panickingConfig.TestConfig.OnOptionsUpdateReceived(bytes); err != nil {
+		return err
+	}
+
+	if panickingConfig.TestProperty == "TestValue2" {
+		panic("failed to apply options")
+	}
+
+	return nil
+}
+
+type recordingLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (recordingLogger *recordingLogger) Log(logLevel ecsclientgowrapper.ECS_LOG_LEVEL, msg string) {
+	recordingLogger.mutex.Lock()
+	defer recordingLogger.mutex.Unlock()
+	recordingLogger.messages = append(recordingLogger.messages, msg)
+}
+
+func (recordingLogger *recordingLogger) contains(substr string) bool {
+	recordingLogger.mutex.Lock()
+	defer recordingLogger.mutex.Unlock()
+
+	for _, message := range recordingLogger.messages {
+		if strings.Contains(message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tests that panics of OnOptionsUpdateReceived and update callbacks are converted into a PanicError for the error callbacks and logged
+func TestEcsGoClientPanicIsolation(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	logger := &recordingLogger{}
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, logger)
+
+	testConfig := &panickingConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	var optionsUpdateErrors []error
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		optionsUpdateErrors = append(optionsUpdateErrors, innerOptionsUpdateError)
+	})
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	require.NotPanics(t, func() {
+		ecsClientInstance.invokeOptionsUpdate(false)
+	})
+	waitForDispatch(ecsClientInstance)
+	require.Len(t, optionsUpdateErrors, 1)
+	require.ErrorIs(t, optionsUpdateErrors[0], ErrPanicked)
+
+	var panicError *PanicError
+	require.ErrorAs(t, optionsUpdateErrors[0], &panicError)
+	require.Equal(t, "failed to apply options", panicError.Value)
+	require.NotEmpty(t, panicError.Stack)
+	require.True(t, logger.contains("goroutine"))
+	require.Contains(t, string(ecsClientInstance.Current(testConfig).Value), "TestValue1")
+
+	_, err = ecsClientInstance.RegisterUpdateEventCallbackFunc(testConfig, func(innerOptionsUpdateError error) {
+		panic(errors.New("callback failed"))
+	})
+	require.NoError(t, err)
+
+	configUpdateEvent2.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue3"}}}`, nil)
+
+	// the panic of the callback is reported to the callbacks after the update has been delivered
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	waitForDispatch(ecsClientInstance)
+	require.Len(t, optionsUpdateErrors, 3)
+	require.NoError(t, optionsUpdateErrors[1])
+	require.ErrorIs(t, optionsUpdateErrors[2], ErrPanicked)
+	require.ErrorContains(t, optionsUpdateErrors[2], "callback failed")
+	require.True(t, logger.contains("update callback of option 'ConfigName' of project team 'TestProjectTeam' panicked"))
+}
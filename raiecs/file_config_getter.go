@@ -0,0 +1,112 @@
+package ecsgoclient
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// DefaultFilePollInterval is the interval in which a FileConfigGetter checks its file for changes if no interval is provided
+const DefaultFilePollInterval = 5 * time.Second
+
+// FileConfigGetter is an EcsConfigGetter that serves the ECS config from a local file instead of ECS, e.g. for local development.
+// The file must contain the same JSON envelope ECS returns: an object per project team plus "Headers" and "ConfigIDs".
+type FileConfigGetter struct {
+	path         string
+	pollInterval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewFileConfigGetter creates a FileConfigGetter for the file at path, which is polled for changes every pollInterval once it is watched
+func NewFileConfigGetter(path string, pollInterval time.Duration) *FileConfigGetter {
+	if pollInterval <= 0 {
+		pollInterval = DefaultFilePollInterval
+	}
+
+	return &FileConfigGetter{
+		path:         path,
+		pollInterval: pollInterval,
+		stop:         make(chan struct{}),
+	}
+}
+
+// NewEcsClientFromFile creates a new ecs client that fetches the config from the file at path and updates the options monitors
+// whenever the file content changes
+func NewEcsClientFromFile(path string, pollInterval time.Duration, logger ecsclientgowrapper.Logger, opts ...ClientOption) (*EcsClient, error) {
+	fileConfigGetter := NewFileConfigGetter(path, pollInterval)
+
+	config, err := fileConfigGetter.GetConfig(ecsclientgowrapper.EcsRequestIdentifiers{})
+	if err != nil {
+		return nil, newEcsError(ErrClientCreationFailed, "", "", err)
+	}
+
+	checkSum, err := getCheckSum([]byte(config))
+	if err != nil {
+		return nil, newEcsError(ErrClientCreationFailed, "", "", err)
+	}
+
+	ecsClient := NewEcsClientFromConfigGetter(fileConfigGetter, logger, opts...)
+	go fileConfigGetter.watch(checkSum, func() {
+		ecsClient.invokeOptionsUpdate(false)
+	})
+
+	return ecsClient, nil
+}
+
+// GetConfig reads the config from the file
+func (fileConfigGetter *FileConfigGetter) GetConfig(ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error) {
+	config, err := os.ReadFile(fileConfigGetter.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file '%v', err: %w", fileConfigGetter.path, err)
+	}
+
+	return string(config), nil
+}
+
+// Close stops watching the file
+func (fileConfigGetter *FileConfigGetter) Close() error {
+	fileConfigGetter.stopOnce.Do(func() {
+		close(fileConfigGetter.stop)
+	})
+
+	return nil
+}
+
+// watch polls the file until Close is called and calls onChange whenever its content differs from the last seen checkSum.
+// Files that can't be read are skipped, so editors that replace the file don't trigger errors.
+func (fileConfigGetter *FileConfigGetter) watch(checkSum string, onChange func()) {
+	watchFile(fileConfigGetter.path, fileConfigGetter.pollInterval, fileConfigGetter.stop, checkSum, os.ReadFile, onChange)
+}
+
+// watchFile polls the file at path every pollInterval until stop is closed and calls onChange whenever the content returned by readFile
+// differs from the last seen checkSum. Files that can't be read are skipped.
+func watchFile(path string, pollInterval time.Duration, stop <-chan struct{}, checkSum string, readFile func(string) ([]byte, error), onChange func()) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		content, err := readFile(path)
+		if err != nil {
+			continue
+		}
+
+		newCheckSum, err := getCheckSum(content)
+		if err != nil || newCheckSum == checkSum {
+			continue
+		}
+
+		checkSum = newCheckSum
+		onChange()
+	}
+}
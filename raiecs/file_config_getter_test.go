@@ -0,0 +1,96 @@
+package ecsgoclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the config is served from the file and changes of the file are applied to the options monitors
+func TestFileConfigGetterConfigUpdateReceived(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "ecs.json")
+	writeConfigFile(t, configPath, validConfigUpdate1)
+
+	ecsClientInstance, err := NewEcsClientFromFile(configPath, time.Millisecond, &NoopLogger{})
+	require.NoError(t, err)
+	defer ecsClientInstance.Close(context.Background())
+
+	configUpdates := make(chan error, 10)
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithUpdateEventCallbackFunc(func(innerOptionsUpdateError error) {
+			configUpdates <- innerOptionsUpdateError
+		}))
+	require.NoError(t, err)
+	require.Equal(t, "TestValue1", monitor.Get().TestProperty)
+
+	writeConfigFile(t, configPath, validConfigUpdate2)
+	require.NoError(t, receiveConfigUpdate(t, configUpdates))
+	require.Equal(t, "TestValue2", monitor.Get().TestProperty)
+
+	writeConfigFile(t, configPath, invalidConfigUpdate)
+	require.ErrorIs(t, receiveConfigUpdate(t, configUpdates), ErrValidationFailed)
+	require.Equal(t, "TestValue2", monitor.Get().TestProperty)
+}
+
+// Tests that creating a client from a missing file fails
+func TestFileConfigGetterMissingFile(t *testing.T) {
+	_, err := NewEcsClientFromFile(filepath.Join(t.TempDir(), "missing.json"), time.Millisecond, &NoopLogger{})
+	require.ErrorIs(t, err, ErrClientCreationFailed)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// Tests that Close stops the watch goroutine
+func TestFileConfigGetterClose(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "ecs.json")
+	writeConfigFile(t, configPath, validConfigUpdate1)
+
+	checkSum, err := getCheckSum([]byte(validConfigUpdate1))
+	require.NoError(t, err)
+
+	fileConfigGetter := NewFileConfigGetter(configPath, time.Millisecond)
+	changes := make(chan struct{}, 10)
+	watchReturned := make(chan struct{})
+	go func() {
+		defer close(watchReturned)
+		fileConfigGetter.watch(checkSum, func() {
+			changes <- struct{}{}
+		})
+	}()
+
+	writeConfigFile(t, configPath, validConfigUpdate2)
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the change of the file has not been detected")
+	}
+
+	require.NoError(t, fileConfigGetter.Close())
+	select {
+	case <-watchReturned:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "watch has not returned after Close")
+	}
+	require.Empty(t, changes)
+}
+
+// receiveConfigUpdate returns the next error received by an update callback, or fails the test if there is none in time
+func receiveConfigUpdate(t *testing.T, configUpdates <-chan error) error {
+	select {
+	case err := <-configUpdates:
+		return err
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no config update has been received")
+		return nil
+	}
+}
+
+// writeConfigFile replaces the config file atomically, so the watcher never reads a partially written file
+func writeConfigFile(t *testing.T, configPath string, config string) {
+	tmpPath := configPath + ".tmp"
+	require.NoError(t, os.WriteFile(tmpPath, []byte(config), 0o600))
+	require.NoError(t, os.Rename(tmpPath, configPath))
+}
@@ -0,0 +1,185 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// MonitorHealth is the update state of an options monitor
+type MonitorHealth struct {
+	// The project team of the options monitor
+	ProjectTeam string
+
+	// The option name of the options monitor
+	OptionName string
+
+	// The time of the last successful update, zero if the options monitor has never been updated successfully.
+	// Updates that found the config unchanged count as successful.
+	LastSuccess time.Time
+
+	// The time of the last failed update, zero if no update has failed yet
+	LastFailure time.Time
+
+	// The number of failed updates since the last successful update
+	ConsecutiveFailures int
+
+	// The error of the last failed update, nil if no update has failed yet
+	LastError error
+
+	// Stale is true while the options monitor serves the last-known-good snapshot, see WithSnapshotDirectory
+	Stale bool
+}
+
+// Staleness returns the time that has passed at now since the last successful update, or -1 if there has been none
+func (monitorHealth MonitorHealth) Staleness(now time.Time) time.Duration {
+	if monitorHealth.LastSuccess.IsZero() {
+		return -1
+	}
+
+	return now.Sub(monitorHealth.LastSuccess)
+}
+
+// recordUpdate records the outcome of an update at now, err is nil for a successful update
+func (ecsOptionsMonitor *EcsOptionsMonitor) recordUpdate(now time.Time, err error) {
+	ecsOptionsMonitor.healthMutex.Lock()
+	defer ecsOptionsMonitor.healthMutex.Unlock()
+
+	if err == nil {
+		ecsOptionsMonitor.health.LastSuccess = now
+		ecsOptionsMonitor.health.ConsecutiveFailures = 0
+		ecsOptionsMonitor.health.Stale = false
+		return
+	}
+
+	ecsOptionsMonitor.health.LastFailure = now
+	ecsOptionsMonitor.health.ConsecutiveFailures++
+	ecsOptionsMonitor.health.LastError = err
+}
+
+// markStale records that the options monitor serves the last-known-good snapshot
+func (ecsOptionsMonitor *EcsOptionsMonitor) markStale() {
+	ecsOptionsMonitor.healthMutex.Lock()
+	defer ecsOptionsMonitor.healthMutex.Unlock()
+
+	ecsOptionsMonitor.health.Stale = true
+}
+
+// Health returns the update state of all options monitors, sorted by project team and option name
+func (ecsClient *EcsClient) Health() []MonitorHealth {
+	ecsClient.callbackFuncsMutex.RLock()
+	monitorHealths := make([]MonitorHealth, 0, len(ecsClient.ecsOptionMonitors))
+	for _, listener := range ecsClient.ecsOptionMonitors {
+		listener.healthMutex.Lock()
+		monitorHealths = append(monitorHealths, listener.health)
+		listener.healthMutex.Unlock()
+	}
+	ecsClient.callbackFuncsMutex.RUnlock()
+
+	sort.Slice(monitorHealths, func(i, j int) bool {
+		if monitorHealths[i].ProjectTeam != monitorHealths[j].ProjectTeam {
+			return monitorHealths[i].ProjectTeam < monitorHealths[j].ProjectTeam
+		}
+		return monitorHealths[i].OptionName < monitorHealths[j].OptionName
+	})
+
+	return monitorHealths
+}
+
+// HealthHandlerOptions are the thresholds of the health check served by NewHealthHandler
+type HealthHandlerOptions struct {
+	// The max time since the last successful update of an options monitor. Zero disables the check.
+	MaxStaleness time.Duration
+
+	// Overrides MaxStaleness for single options monitors, keyed by "<projectTeam>/<optionName>"
+	MaxStalenessByOption map[string]time.Duration
+
+	// The max number of consecutive failed updates an options monitor is still healthy with. Zero disables the check.
+	MaxConsecutiveFailures int
+}
+
+// healthResponse is the JSON body served by the health handler
+type healthResponse struct {
+	Healthy  bool                    `json:"healthy"`
+	Monitors []monitorHealthResponse `json:"monitors"`
+}
+
+// monitorHealthResponse is the JSON representation of MonitorHealth
+type monitorHealthResponse struct {
+	ProjectTeam         string     `json:"projectTeam"`
+	OptionName          string     `json:"optionName"`
+	Healthy             bool       `json:"healthy"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure         *time.Time `json:"lastFailure,omitempty"`
+	StalenessSeconds    *float64   `json:"stalenessSeconds,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	Stale               bool       `json:"stale,omitempty"`
+}
+
+// NewHealthHandler creates an http.Handler for readiness probes. It responds with 200 if all options monitors of ecsClient are within the
+// thresholds of options and 503 otherwise, with the state of each options monitor as JSON body.
+func NewHealthHandler(ecsClient *EcsClient, options HealthHandlerOptions) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		now := ecsClient.now()
+		response := healthResponse{Healthy: true, Monitors: []monitorHealthResponse{}}
+
+		for _, monitorHealth := range ecsClient.Health() {
+			monitorResponse := monitorHealthResponse{
+				ProjectTeam:         monitorHealth.ProjectTeam,
+				OptionName:          monitorHealth.OptionName,
+				Healthy:             options.isHealthy(monitorHealth, now),
+				ConsecutiveFailures: monitorHealth.ConsecutiveFailures,
+				Stale:               monitorHealth.Stale,
+			}
+
+			if !monitorHealth.LastSuccess.IsZero() {
+				lastSuccess := monitorHealth.LastSuccess
+				stalenessSeconds := monitorHealth.Staleness(now).Seconds()
+				monitorResponse.LastSuccess = &lastSuccess
+				monitorResponse.StalenessSeconds = &stalenessSeconds
+			}
+
+			if !monitorHealth.LastFailure.IsZero() {
+				lastFailure := monitorHealth.LastFailure
+				monitorResponse.LastFailure = &lastFailure
+			}
+
+			if monitorHealth.LastError != nil {
+				monitorResponse.LastError = monitorHealth.LastError.Error()
+			}
+
+			response.Healthy = response.Healthy && monitorResponse.Healthy
+			response.Monitors = append(response.Monitors, monitorResponse)
+		}
+
+		statusCode := http.StatusOK
+		if !response.Healthy {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		responseWriter.Header().Set("Content-Type", "application/json")
+		responseWriter.WriteHeader(statusCode)
+		_ = json.NewEncoder(responseWriter).Encode(response)
+	})
+}
+
+// isHealthy returns whether monitorHealth is within the thresholds at now
+func (options HealthHandlerOptions) isHealthy(monitorHealth MonitorHealth, now time.Time) bool {
+	if options.MaxConsecutiveFailures > 0 && monitorHealth.ConsecutiveFailures > options.MaxConsecutiveFailures {
+		return false
+	}
+
+	maxStaleness := options.MaxStaleness
+	if optionMaxStaleness, ok := options.MaxStalenessByOption[monitorHealth.ProjectTeam+"/"+monitorHealth.OptionName]; ok {
+		maxStaleness = optionMaxStaleness
+	}
+
+	if maxStaleness <= 0 {
+		return true
+	}
+
+	staleness := monitorHealth.Staleness(now)
+	return staleness >= 0 && staleness <= maxStaleness
+}
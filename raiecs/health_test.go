@@ -0,0 +1,124 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the health of the options monitors tracks successful and failed updates
+func TestEcsGoClientHealth(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ecsClientInstance.now = func() time.Time { return now }
+
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	missingMonitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "MissingConfigName")
+	require.ErrorIs(t, err, ErrOptionNotFound)
+
+	health := ecsClientInstance.Health()
+	require.Len(t, health, 2)
+	require.Equal(t, 1, health[1].ConsecutiveFailures)
+
+	missingMonitor.Remove()
+	health = ecsClientInstance.Health()
+	require.Len(t, health, 1)
+	require.Equal(t, MonitorHealth{ProjectTeam: "TestProjectTeam", OptionName: "ConfigName", LastSuccess: now}, health[0])
+
+	configUpdateEvent1.Unset()
+	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("some error"))
+
+	start := now
+	for i := 1; i <= 2; i++ {
+		now = now.Add(time.Minute)
+		ecsClientInstance.invokeOptionsUpdate(false)
+	}
+
+	health = ecsClientInstance.Health()
+	require.Equal(t, start, health[0].LastSuccess)
+	require.Equal(t, now, health[0].LastFailure)
+	require.Equal(t, 2, health[0].ConsecutiveFailures)
+	require.ErrorIs(t, health[0].LastError, ErrFetchFailed)
+	require.Equal(t, 2*time.Minute, health[0].Staleness(now))
+
+	configUpdateEvent2.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	// an unchanged config is a successful update as well
+	now = now.Add(time.Minute)
+	ecsClientInstance.invokeOptionsUpdate(false)
+
+	health = ecsClientInstance.Health()
+	require.Equal(t, now, health[0].LastSuccess)
+	require.Equal(t, 0, health[0].ConsecutiveFailures)
+	require.Error(t, health[0].LastError)
+}
+
+// Tests that the health handler responds with 503 once an options monitor exceeds the thresholds
+func TestHealthHandler(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ecsClientInstance.now = func() time.Time { return now }
+
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("some error"))
+
+	serveHealth := func(options HealthHandlerOptions) (int, healthResponse) {
+		recorder := httptest.NewRecorder()
+		NewHealthHandler(ecsClientInstance, options).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		var response healthResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		return recorder.Code, response
+	}
+
+	options := HealthHandlerOptions{MaxStaleness: 10 * time.Minute, MaxConsecutiveFailures: 3}
+
+	now = now.Add(5 * time.Minute)
+	ecsClientInstance.invokeOptionsUpdate(false)
+	statusCode, response := serveHealth(options)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.True(t, response.Healthy)
+	require.Len(t, response.Monitors, 1)
+	require.Equal(t, 1, response.Monitors[0].ConsecutiveFailures)
+	require.Equal(t, float64(300), *response.Monitors[0].StalenessSeconds)
+	require.Contains(t, response.Monitors[0].LastError, "some error")
+
+	statusCode, _ = serveHealth(HealthHandlerOptions{MaxStaleness: 10 * time.Minute, MaxStalenessByOption: map[string]time.Duration{"TestProjectTeam/ConfigName": time.Minute}})
+	require.Equal(t, http.StatusServiceUnavailable, statusCode)
+
+	now = now.Add(6 * time.Minute)
+	statusCode, response = serveHealth(options)
+	require.Equal(t, http.StatusServiceUnavailable, statusCode)
+	require.False(t, response.Healthy)
+	require.False(t, response.Monitors[0].Healthy)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	ecsClientInstance.invokeOptionsUpdate(false)
+	statusCode, _ = serveHealth(HealthHandlerOptions{MaxConsecutiveFailures: 3})
+	require.Equal(t, http.StatusOK, statusCode)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	statusCode, _ = serveHealth(HealthHandlerOptions{MaxConsecutiveFailures: 3})
+	require.Equal(t, http.StatusServiceUnavailable, statusCode)
+
+	statusCode, _ = serveHealth(HealthHandlerOptions{})
+	require.Equal(t, http.StatusOK, statusCode)
+}
@@ -0,0 +1,76 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonChangedPaths returns the sorted JSON pointers (RFC 6901) of all values that differ between previous and current.
+// Objects are compared key by key and arrays index by index, a changed value of any other type is reported with its own path.
+// If previous is nil, the whole document is reported as changed with the root pointer "".
+func jsonChangedPaths(previous json.RawMessage, current json.RawMessage) ([]string, error) {
+	if previous == nil {
+		return []string{""}, nil
+	}
+
+	var previousValue, currentValue any
+	if err := json.Unmarshal(previous, &previousValue); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(current, &currentValue); err != nil {
+		return nil, err
+	}
+
+	changedPaths := []string{}
+	collectChangedPaths("", previousValue, currentValue, &changedPaths)
+	sort.Strings(changedPaths)
+
+	return changedPaths, nil
+}
+
+// collectChangedPaths appends the JSON pointers of the values that differ between previous and current below path to changedPaths
+func collectChangedPaths(path string, previous any, current any, changedPaths *[]string) {
+	switch previousValue := previous.(type) {
+	case map[string]any:
+		if currentValue, ok := current.(map[string]any); ok {
+			for key, value := range previousValue {
+				collectChangedPaths(path+"/"+escapeJSONPointerToken(key), value, currentValue[key], changedPaths)
+			}
+
+			for key := range currentValue {
+				if _, ok := previousValue[key]; !ok {
+					*changedPaths = append(*changedPaths, path+"/"+escapeJSONPointerToken(key))
+				}
+			}
+
+			return
+		}
+	case []any:
+		if currentValue, ok := current.([]any); ok {
+			for i := 0; i < len(previousValue) || i < len(currentValue); i++ {
+				elementPath := path + "/" + strconv.Itoa(i)
+				if i >= len(previousValue) || i >= len(currentValue) {
+					*changedPaths = append(*changedPaths, elementPath)
+					continue
+				}
+
+				collectChangedPaths(elementPath, previousValue[i], currentValue[i], changedPaths)
+			}
+
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(previous, current) {
+		*changedPaths = append(*changedPaths, path)
+	}
+}
+
+// escapeJSONPointerToken escapes a key to be used as token in a JSON pointer
+func escapeJSONPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,39 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the JSON pointers of all changed, added and removed values are reported
+func TestJSONChangedPaths(t *testing.T) {
+	testCases := []struct {
+		name     string
+		previous string
+		current  string
+		expected []string
+	}{
+		{"NoPrevious", "", `{"a": 1}`, []string{""}},
+		{"Unchanged", `{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1}`, []string{}},
+		{"ChangedValue", `{"a": 1, "b": {"c": "x", "d": true}}`, `{"a": 1, "b": {"c": "y", "d": true}}`, []string{"/b/c"}},
+		{"AddedAndRemovedKeys", `{"a": 1, "b": 2}`, `{"b": 2, "c": 3}`, []string{"/a", "/c"}},
+		{"ArrayElements", `{"a": [1, 2, 3]}`, `{"a": [1, 5]}`, []string{"/a/1", "/a/2"}},
+		{"ChangedType", `{"a": {"b": 1}}`, `{"a": [1]}`, []string{"/a"}},
+		{"EscapedKeys", `{"a/b": 1, "c~d": 1}`, `{"a/b": 2, "c~d": 2}`, []string{"/a~1b", "/c~0d"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var previous json.RawMessage
+			if testCase.previous != "" {
+				previous = json.RawMessage(testCase.previous)
+			}
+
+			changedPaths, err := jsonChangedPaths(previous, json.RawMessage(testCase.current))
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, changedPaths)
+		})
+	}
+}
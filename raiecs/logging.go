@@ -0,0 +1,26 @@
+package ecsgoclient
+
+import (
+	"log/slog"
+)
+
+// Keys of the structured attributes of the log messages of the EcsClient
+const (
+	LogKeyProjectTeam    = "project_team"
+	LogKeyOptionName     = "option"
+	LogKeyCheckSum       = "checksum"
+	LogKeyConfigID       = "config_id"
+	LogKeyConfig         = "config"
+	LogKeyEventType      = "event_type"
+	LogKeyError          = "error"
+	LogKeyStack          = "stack"
+	LogKeySavedAt        = "saved_at"
+	LogKeyDefaultsOnly   = "defaults_only"
+	LogKeyOverrides      = "overrides"
+	LogKeyOverrideSource = "override_source"
+)
+
+// optionLogAttrs returns the log attributes identifying the options monitor of optionName of projectTeam
+func optionLogAttrs(projectTeam string, optionName string) []slog.Attr {
+	return []slog.Attr{slog.String(LogKeyProjectTeam, projectTeam), slog.String(LogKeyOptionName, optionName)}
+}
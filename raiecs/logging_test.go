@@ -0,0 +1,85 @@
+package ecsgoclient
+
+import (
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type logRecord struct {
+	logLevel ecsclientgowrapper.ECS_LOG_LEVEL
+	msg      string
+	attrs    map[string]string
+}
+
+type structuredRecordingLogger struct {
+	mutex   sync.Mutex
+	records []logRecord
+}
+
+func (structuredRecordingLogger *structuredRecordingLogger) Log(logLevel ecsclientgowrapper.ECS_LOG_LEVEL, msg string) {
+	structuredRecordingLogger.LogAttrs(logLevel, msg)
+}
+
+func (structuredRecordingLogger *structuredRecordingLogger) LogAttrs(logLevel ecsclientgowrapper.ECS_LOG_LEVEL, msg string, attrs ...slog.Attr) {
+	structuredRecordingLogger.mutex.Lock()
+	defer structuredRecordingLogger.mutex.Unlock()
+
+	record := logRecord{logLevel: logLevel, msg: msg, attrs: make(map[string]string)}
+	for _, attr := range attrs {
+		record.attrs[attr.Key] = attr.Value.String()
+	}
+	structuredRecordingLogger.records = append(structuredRecordingLogger.records, record)
+}
+
+func (structuredRecordingLogger *structuredRecordingLogger) find(msg string) *logRecord {
+	structuredRecordingLogger.mutex.Lock()
+	defer structuredRecordingLogger.mutex.Unlock()
+
+	for _, record := range structuredRecordingLogger.records {
+		if record.msg == msg {
+			return &record
+		}
+	}
+	return nil
+}
+
+// Tests that the log messages of the client carry the project team, option and checksum as attributes and honor the log level
+func TestEcsGoClientStructuredLogging(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	logger := &structuredRecordingLogger{}
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, logger)
+
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	record := logger.find("Received ECS config")
+	require.NotNil(t, record)
+	require.Equal(t, ecsclientgowrapper.ECS_LOG_LEVEL_INFORMATION, record.logLevel)
+	require.Equal(t, "TestProjectTeam", record.attrs[LogKeyProjectTeam])
+	require.Equal(t, "ConfigName", record.attrs[LogKeyOptionName])
+	require.Equal(t, ecsClientInstance.Current(monitor).CheckSum, record.attrs[LogKeyCheckSum])
+	require.Equal(t, "P-D-1129197-1-172", record.attrs[LogKeyConfigID])
+
+	_, err = AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "MissingConfigName")
+	require.Error(t, err)
+
+	record = logger.find("on options update func failed")
+	require.NotNil(t, record)
+	require.Equal(t, "MissingConfigName", record.attrs[LogKeyOptionName])
+	require.Contains(t, record.attrs[LogKeyError], "MissingConfigName")
+
+	logger = &structuredRecordingLogger{}
+	ecsClientInstance = NewEcsClientFromConfigGetter(&ecsConfigGetter, logger, WithLogLevel(ecsclientgowrapper.ECS_LOG_LEVEL_WARNING))
+
+	_, err = AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Nil(t, logger.find("Received ECS config"))
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/raiecs/ecsclientgowrapper"
 
@@ -21,6 +24,7 @@ var (
 	ProjectTeam     string
 	EnvironmentName string
 	ServiceName     string
+	ConfigFile      string
 )
 
 func init() {
@@ -28,6 +32,7 @@ func init() {
 	CMD.PersistentFlags().StringVar(&ProjectTeam, "projectTeam", "", "project team")
 	CMD.PersistentFlags().StringVar(&EnvironmentName, "environment", "", "environment")
 	CMD.PersistentFlags().StringVar(&ServiceName, "service", "", "service")
+	CMD.PersistentFlags().StringVar(&ConfigFile, "configFile", "", "serve the config from a local ECS JSON file instead of ECS")
 }
 
 func main() {
@@ -45,38 +50,17 @@ type EcsModerationConfig struct {
 	PolicyId string `json:"PolicyId"`
 }
 
-func (ecsConfig *EcsConfig) OnOptionsUpdateReceived(bytes []byte) error {
-	var parsedConfig EcsConfig
-	if err := json.Unmarshal(bytes, &parsedConfig); err != nil {
-		return fmt.Errorf("failed to unmarshal options, err: %v", err)
-	}
-
-	if err := parsedConfig.Validate(); err != nil {
-		return err
-	}
-
-	*ecsConfig = parsedConfig
-	return nil
-}
-
 func (testConfig EcsConfig) Validate() error {
 	return nil
 }
 
-type ConsoleLogger struct {
-}
-
-func (consoleLogger ConsoleLogger) Log(logLevel ecsclientgowrapper.ECS_LOG_LEVEL, msg string) {
-	fmt.Printf("Received log. %v: %v \n", logLevel, msg)
-}
-
 func runExample(command *cobra.Command, args []string) {
 	targetFilters := map[string][]string{
 		"EnvironmentName": {EnvironmentName},
 		"ServiceName":     {ServiceName},
 	}
 
-	consoleLogger := ConsoleLogger{}
+	consoleLogger := ecsclientgowrapper.NewSlogLogger(slog.NewTextHandler(os.Stdout, nil))
 
 	options := ecsgoclient.EcsClientOptions{
 		Client:        ClientName,
@@ -86,37 +70,50 @@ func runExample(command *cobra.Command, args []string) {
 		Logger:        consoleLogger,
 	}
 
-	ecsClientInstance, err := ecsgoclient.NewEcsClient(options)
-	if err != nil {
-		fmt.Printf("Creating ECS client failed with error: %v", err.Error())
-		os.Exit(1)
+	var ecsClientInstance *ecsgoclient.EcsClient
+	var err error
+	if ConfigFile != "" {
+		ecsClientInstance, err = ecsgoclient.NewEcsClientFromFile(ConfigFile, ecsgoclient.DefaultFilePollInterval, consoleLogger)
+	} else {
+		ecsClientInstance, err = ecsgoclient.NewEcsClient(options)
 	}
-
-	ecsConfig := &EcsConfig{}
-	err = ecsClientInstance.AddOptionsMonitorToEcsClient(ecsConfig, ProjectTeam, "EcsConfig")
 	if err != nil {
 		fmt.Printf("Creating ECS client failed with error: %v", err.Error())
 		os.Exit(1)
 	}
 
-	err = ecsClientInstance.RegisterUpdateEventCallbackFunc(ecsConfig, func(innerOptionsUpdateError error) {
-		if innerOptionsUpdateError != nil {
-			fmt.Printf("Received error. %v", innerOptionsUpdateError.Error())
-			return
+	// deferred calls don't run on os.Exit, so the client is closed explicitly on every path
+	closeClient := func() {
+		if err := ecsClientInstance.Close(context.Background()); err != nil {
+			fmt.Printf("Closing ECS client failed with error: %v", err.Error())
 		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ecsConfigMonitor, err := ecsgoclient.AddTypedMonitorContext[EcsConfig](ctx, ecsClientInstance, ProjectTeam, "EcsConfig",
+		ecsgoclient.WithUpdateEventCallbackFunc(func(innerOptionsUpdateError error) {
+			if innerOptionsUpdateError != nil {
+				fmt.Printf("Received error. %v", innerOptionsUpdateError.Error())
+				return
+			}
 
-		fmt.Printf("Received config update event")
-	})
+			fmt.Printf("Received config update event")
+		}))
 	if err != nil {
-		fmt.Printf("Registering event callback failed with error: %v", err.Error())
+		fmt.Printf("Adding the ECS config monitor failed with error: %v", err.Error())
+		closeClient()
 		os.Exit(1)
 	}
 
-	ecsConfigString, err := json.Marshal(*ecsConfig)
+	ecsConfigString, err := json.Marshal(ecsConfigMonitor.Get())
 	if err != nil {
 		fmt.Printf("%v", err.Error())
+		closeClient()
 		os.Exit(1)
 	}
 
 	fmt.Printf("Received configuration: \"%v\"\n", string(ecsConfigString))
+	closeClient()
 }
@@ -0,0 +1,79 @@
+package ecsgoclient
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// Error categories returned by ErrorCategory
+const (
+	ErrorCategoryTimeout             = "timeout"
+	ErrorCategoryCanceled            = "canceled"
+	ErrorCategoryNative              = "native"
+	ErrorCategoryClientClosed        = "client_closed"
+	ErrorCategoryInvalidConfig       = "invalid_config"
+	ErrorCategoryProjectTeamNotFound = "project_team_not_found"
+	ErrorCategoryOptionNotFound      = "option_not_found"
+	ErrorCategoryValidationFailed    = "validation_failed"
+	ErrorCategoryPanicked            = "panicked"
+	ErrorCategoryOther               = "other"
+)
+
+// MetricsRecorder receives the metrics of an EcsClient. The methods are called on the update path and must not block.
+type MetricsRecorder interface {
+	// ObserveFetch records a config fetch that took duration. err is nil if the fetch succeeded.
+	ObserveFetch(duration time.Duration, err error)
+
+	// ObserveEvent records an event of the ecs C library
+	ObserveEvent(eventType ecsclientgowrapper.ECS_EVENT_TYPE)
+
+	// ObserveUpdate records the evaluation of a fetched config by the options monitor of optionName of projectTeam.
+	// err is nil if the config was accepted, changed is true if the accepted config differs from the previous one.
+	// Applying the last received config again after the overrides file changed is only recorded if it failed.
+	ObserveUpdate(projectTeam string, optionName string, changed bool, err error)
+
+	// ObserveRemove records that the last options monitor of optionName of projectTeam has been removed from the EcsClient, e.g. to stop
+	// reporting its staleness
+	ObserveRemove(projectTeam string, optionName string)
+}
+
+// noopMetricsRecorder is the MetricsRecorder of EcsClients without metrics
+type noopMetricsRecorder struct{}
+
+func (noopMetricsRecorder) ObserveFetch(duration time.Duration, err error) {}
+
+func (noopMetricsRecorder) ObserveEvent(eventType ecsclientgowrapper.ECS_EVENT_TYPE) {}
+
+func (noopMetricsRecorder) ObserveUpdate(projectTeam string, optionName string, changed bool, err error) {
+}
+
+func (noopMetricsRecorder) ObserveRemove(projectTeam string, optionName string) {}
+
+// ErrorCategory classifies err into one of the ErrorCategory constants, e.g. to be used as metric label
+func ErrorCategory(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCategoryTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCategoryCanceled
+	case errors.Is(err, ecsclientgowrapper.ErrOperationFailed), errors.Is(err, ecsclientgowrapper.ErrNativeUnavailable), errors.Is(err, ecsclientgowrapper.ErrClientDestroyed):
+		return ErrorCategoryNative
+	case errors.Is(err, ErrClientClosed):
+		return ErrorCategoryClientClosed
+	case errors.Is(err, ErrPanicked):
+		return ErrorCategoryPanicked
+	case errors.Is(err, ErrInvalidConfig):
+		return ErrorCategoryInvalidConfig
+	case errors.Is(err, ErrProjectTeamNotFound):
+		return ErrorCategoryProjectTeamNotFound
+	case errors.Is(err, ErrOptionNotFound):
+		return ErrorCategoryOptionNotFound
+	case errors.Is(err, ErrValidationFailed):
+		return ErrorCategoryValidationFailed
+	default:
+		return ErrorCategoryOther
+	}
+}
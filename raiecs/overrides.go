@@ -0,0 +1,303 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// EnvironmentOverridePrefix is the prefix of the environment variables read by WithEnvironmentOverrides
+const EnvironmentOverridePrefix = "ECS_OVERRIDE_"
+
+// WithEnvironmentOverrides overrides single values of the options with environment variables named
+// ECS_OVERRIDE_<TEAM>_<OPTION>_<PATH>. TEAM and OPTION are the project team and option name with all characters other than letters
+// and digits replaced by '_', PATH are the keys (or array indexes) leading to the value, separated by "__". All parts are matched
+// case-insensitively, e.g. ECS_OVERRIDE_MYTEAM_MYOPTION_ENDPOINTS__0__TIMEOUT sets /Endpoints/0/Timeout of MyOption of MyTeam.
+// The value is parsed as JSON if possible and used as string otherwise. Environment overrides take precedence over WithOverridesFile.
+func WithEnvironmentOverrides() ClientOption {
+	return func(ecsClient *EcsClient) {
+		ecsClient.environmentOverrides = true
+	}
+}
+
+// WithOverridesFile overrides single values of the options with the file at path, so overrides can be added and removed without a restart.
+// The file is polled for changes every DefaultFilePollInterval (see WithOverridesFilePollInterval) and a change is applied to the last
+// option received from ECS right away. The file maps project teams and option names to JSON pointers (RFC 6901) and their values,
+// e.g. {"MyTeam": {"MyOption": {"/Endpoints/0/Timeout": 30}}}. A missing file is ignored.
+func WithOverridesFile(path string) ClientOption {
+	return func(ecsClient *EcsClient) {
+		ecsClient.overridesFile = path
+	}
+}
+
+// WithOverridesFilePollInterval sets the interval in which the file of WithOverridesFile is checked for changes
+func WithOverridesFilePollInterval(pollInterval time.Duration) ClientOption {
+	return func(ecsClient *EcsClient) {
+		ecsClient.overridesPollInterval = pollInterval
+	}
+}
+
+// watchOverridesFile polls the overrides file until the client is closed and applies the overrides again whenever the file changes
+func (ecsClient *EcsClient) watchOverridesFile() {
+	pollInterval := ecsClient.overridesPollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultFilePollInterval
+	}
+
+	// a missing file has no overrides, so removing it is a change as well
+	readFile := func(path string) ([]byte, error) {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return content, err
+	}
+
+	var checkSum string
+	if content, err := readFile(ecsClient.overridesFile); err == nil {
+		checkSum, _ = getCheckSum(content)
+	}
+
+	ecsClient.overridesWatchStop = make(chan struct{})
+	go watchFile(ecsClient.overridesFile, pollInterval, ecsClient.overridesWatchStop, checkSum, readFile, ecsClient.reapplyOptions)
+}
+
+// OptionOverride is an override that has been applied to an option
+type OptionOverride struct {
+	// The JSON pointer (RFC 6901) of the overridden value within the option
+	Path string
+
+	// Where the override has been configured, "env:<variable name>" or "file:<path>"
+	Source string
+}
+
+// configOverride is an override of the value at a path of an option
+type configOverride struct {
+	tokens []string
+	value  any
+	source string
+
+	// caseInsensitive matches the tokens case-insensitively against existing keys, as environment variable names are usually upper case
+	caseInsensitive bool
+}
+
+// collectOverrides returns the overrides of the option optionName of projectTeam, ordered by increasing precedence
+func (ecsClient *EcsClient) collectOverrides(projectTeam string, optionName string) []configOverride {
+	var overrides []configOverride
+
+	if ecsClient.overridesFile != "" {
+		fileOverrides, err := readOverridesFile(ecsClient.overridesFile, projectTeam, optionName)
+		if err != nil {
+			ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, "reading the overrides file failed",
+				append(optionLogAttrs(projectTeam, optionName), errorLogAttrs(err)...)...)
+		}
+		overrides = append(overrides, fileOverrides...)
+	}
+
+	if ecsClient.environmentOverrides {
+		overrides = append(overrides, environmentOverrides(ecsClient.environ(), projectTeam, optionName)...)
+	}
+
+	return overrides
+}
+
+// readOverridesFile reads the overrides of optionName of projectTeam from the overrides file at path
+func readOverridesFile(path string, projectTeam string, optionName string) ([]configOverride, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file map[string]map[string]map[string]any
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	var overrides []configOverride
+	for pointer, value := range file[projectTeam][optionName] {
+		if pointer != "" && !strings.HasPrefix(pointer, "/") {
+			return nil, fmt.Errorf("invalid JSON pointer '%v'", pointer)
+		}
+
+		var tokens []string
+		if pointer != "" {
+			for _, token := range strings.Split(pointer[1:], "/") {
+				tokens = append(tokens, unescapeJSONPointerToken(token))
+			}
+		}
+		overrides = append(overrides, configOverride{tokens: tokens, value: value, source: "file:" + path})
+	}
+
+	// the map has no order, so the overrides are sorted to apply overlapping paths deterministically, parents first
+	sortOverrides(overrides)
+	return overrides, nil
+}
+
+// environmentOverrides returns the overrides of optionName of projectTeam from the environment variables in environ
+func environmentOverrides(environ []string, projectTeam string, optionName string) []configOverride {
+	prefix := EnvironmentOverridePrefix + environmentName(projectTeam) + "_" + environmentName(optionName) + "_"
+
+	var overrides []configOverride
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if len(name) <= len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+			continue
+		}
+
+		var parsedValue any
+		if err := json.Unmarshal([]byte(value), &parsedValue); err != nil {
+			parsedValue = value
+		}
+
+		overrides = append(overrides, configOverride{
+			tokens:          strings.Split(name[len(prefix):], "__"),
+			value:           parsedValue,
+			source:          "env:" + name,
+			caseInsensitive: true,
+		})
+	}
+
+	sortOverrides(overrides)
+	return overrides
+}
+
+// environmentName converts a project team or option name to its form in environment variable names
+func environmentName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// sortOverrides sorts the overrides by path depth and source, so parents are overridden before their children
+func sortOverrides(overrides []configOverride) {
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return overrideLess(overrides[i], overrides[j])
+	})
+}
+
+// overrideLess orders overrides by path depth first and by source and path second
+func overrideLess(a configOverride, b configOverride) bool {
+	if len(a.tokens) != len(b.tokens) {
+		return len(a.tokens) < len(b.tokens)
+	}
+	if a.source != b.source {
+		return a.source < b.source
+	}
+	return strings.Join(a.tokens, "/") < strings.Join(b.tokens, "/")
+}
+
+// applyOverrides applies the overrides to the parsed option document without modifying it and returns the overridden document and the
+// applied overrides. Overrides that can't be applied, e.g. because an array index is out of range, are logged and skipped.
+func (ecsClient *EcsClient) applyOverrides(projectTeam string, optionName string, document any, overrides []configOverride) (any, []OptionOverride) {
+	var applied []OptionOverride
+	for _, override := range overrides {
+		overridden, path, err := setJSONValue(document, override.tokens, override.value, override.caseInsensitive)
+		if err != nil {
+			attrs := append(optionLogAttrs(projectTeam, optionName), slog.String(LogKeyOverrideSource, override.source))
+			ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, "ignored config override",
+				append(attrs, errorLogAttrs(err)...)...)
+			continue
+		}
+
+		document = overridden
+		applied = append(applied, OptionOverride{Path: path, Source: override.source})
+	}
+
+	return document, applied
+}
+
+// effectiveOption applies the defaults and overrides of ecsOptionsMonitor to the parsed option ecsOption as received from ECS, found is false
+// if the option is missing in ECS. Returns the effective option and the applied overrides.
+func (ecsClient *EcsClient) effectiveOption(ecsOptionsMonitor *EcsOptionsMonitor, ecsOption any, found bool) (any, []OptionOverride) {
+	optionConfig := ecsOption
+	if ecsOptionsMonitor.hasDefaults {
+		if found {
+			optionConfig = mergeJSONValues(ecsOptionsMonitor.defaults, ecsOption)
+		} else {
+			optionConfig = ecsOptionsMonitor.defaults
+		}
+	}
+
+	// the overrides of the operators are applied on top of ECS and the defaults
+	var appliedOverrides []OptionOverride
+	if overrides := ecsClient.collectOverrides(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName); len(overrides) > 0 {
+		optionConfig, appliedOverrides = ecsClient.applyOverrides(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName, optionConfig, overrides)
+	}
+
+	return optionConfig, appliedOverrides
+}
+
+// setJSONValue returns a copy of document with value set at the path of tokens and the JSON pointer of the set value. Only the objects
+// and arrays along the path are copied, missing objects are created.
+func setJSONValue(document any, tokens []string, value any, caseInsensitive bool) (any, string, error) {
+	if len(tokens) == 0 {
+		return value, "", nil
+	}
+
+	switch typedDocument := document.(type) {
+	case []any:
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil || index < 0 || index >= len(typedDocument) {
+			return nil, "", fmt.Errorf("invalid array index '%v'", tokens[0])
+		}
+
+		child, path, err := setJSONValue(typedDocument[index], tokens[1:], value, caseInsensitive)
+		if err != nil {
+			return nil, "", err
+		}
+
+		copied := append([]any(nil), typedDocument...)
+		copied[index] = child
+		return copied, "/" + tokens[0] + path, nil
+	case map[string]any:
+		key := tokens[0]
+		if caseInsensitive {
+			for existingKey := range typedDocument {
+				if strings.EqualFold(existingKey, key) {
+					key = existingKey
+					break
+				}
+			}
+		}
+
+		child, path, err := setJSONValue(typedDocument[key], tokens[1:], value, caseInsensitive)
+		if err != nil {
+			return nil, "", err
+		}
+
+		copied := make(map[string]any, len(typedDocument)+1)
+		for existingKey, existingValue := range typedDocument {
+			copied[existingKey] = existingValue
+		}
+		copied[key] = child
+		return copied, "/" + escapeJSONPointerToken(key) + path, nil
+	case nil:
+		return setJSONValue(map[string]any{}, tokens, value, caseInsensitive)
+	default:
+		return nil, "", fmt.Errorf("can't set '%v' on a %T value", tokens[0], document)
+	}
+}
+
+// overridesLogValue formats the applied overrides for the log, without their values as they may be secret
+func overridesLogValue(overrides []OptionOverride) string {
+	formatted := make([]string, 0, len(overrides))
+	for _, override := range overrides {
+		formatted = append(formatted, override.Path+" ("+override.Source+")")
+	}
+
+	return strings.Join(formatted, ", ")
+}
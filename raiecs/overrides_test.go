@@ -0,0 +1,204 @@
+package ecsgoclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the environment variables of the option are parsed into overrides and the ones of other options are ignored
+func TestEnvironmentOverrides(t *testing.T) {
+	overrides := environmentOverrides([]string{
+		"ECS_OVERRIDE_TEST_PROJECT_TEAM_CONFIGNAME_ENDPOINTS__0__TIMEOUT=30",
+		"ecs_override_test_project_team_configname_testproperty=not json",
+		"ECS_OVERRIDE_TEST_PROJECT_TEAM_OTHERCONFIG_TESTPROPERTY=other",
+		"PATH=/usr/bin",
+	}, "Test-Project-Team", "ConfigName")
+
+	require.Equal(t, []configOverride{
+		{tokens: []string{"testproperty"}, value: "not json", source: "env:ecs_override_test_project_team_configname_testproperty", caseInsensitive: true},
+		{tokens: []string{"ENDPOINTS", "0", "TIMEOUT"}, value: float64(30), source: "env:ECS_OVERRIDE_TEST_PROJECT_TEAM_CONFIGNAME_ENDPOINTS__0__TIMEOUT", caseInsensitive: true},
+	}, overrides)
+}
+
+// Tests that overrides set values without modifying the document, reuse the case of existing keys and create missing objects
+func TestSetJSONValue(t *testing.T) {
+	var document any
+	require.NoError(t, json.Unmarshal([]byte(`{"Endpoints": [{"Timeout": 10}], "Name": "a"}`), &document))
+
+	overridden, path, err := setJSONValue(document, []string{"ENDPOINTS", "0", "TIMEOUT"}, float64(30), true)
+	require.NoError(t, err)
+	require.Equal(t, "/Endpoints/0/Timeout", path)
+
+	overridden, path, err = setJSONValue(overridden, []string{"Retry", "Count"}, float64(3), false)
+	require.NoError(t, err)
+	require.Equal(t, "/Retry/Count", path)
+
+	marshaled, err := json.Marshal(overridden)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Endpoints": [{"Timeout": 30}], "Name": "a", "Retry": {"Count": 3}}`, string(marshaled))
+
+	unchangedDocument, err := json.Marshal(document)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Endpoints": [{"Timeout": 10}], "Name": "a"}`, string(unchangedDocument))
+
+	_, _, err = setJSONValue(document, []string{"Endpoints", "1", "Timeout"}, float64(30), false)
+	require.Error(t, err)
+
+	_, _, err = setJSONValue(document, []string{"Name", "First"}, "b", false)
+	require.Error(t, err)
+}
+
+// Tests that the overrides of the environment and the overrides file are applied before the options update and reported
+func TestEcsGoClientOverrides(t *testing.T) {
+	overridesFile := filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(overridesFile, []byte(`{"TestProjectTeam": {"ConfigName": {"/TestProperty": "FileValue", "/TestIntegerWithMaxValue100": 5}}}`), 0o600))
+
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue1", "TestIntegerWithMaxValue100": 1}}}`, nil)
+
+	logger := &structuredRecordingLogger{}
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, logger, WithEnvironmentOverrides(), WithOverridesFile(overridesFile))
+	ecsClientInstance.environ = func() []string {
+		return []string{"ECS_OVERRIDE_TESTPROJECTTEAM_CONFIGNAME_TESTINTEGERWITHMAXVALUE100=7"}
+	}
+
+	var events []OptionsUpdateEvent
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithOptionsUpdateEventCallbackFunc(func(event OptionsUpdateEvent) {
+			events = append(events, event)
+		}))
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "FileValue", TestIntegerWithMaxValue100: 7}, monitor.Get())
+
+	record := logger.find("Received ECS config")
+	require.NotNil(t, record)
+	require.Equal(t, "/TestIntegerWithMaxValue100 (file:"+overridesFile+"), /TestProperty (file:"+overridesFile+"), "+
+		"/TestIntegerWithMaxValue100 (env:ECS_OVERRIDE_TESTPROJECTTEAM_CONFIGNAME_TESTINTEGERWITHMAXVALUE100)", record.attrs[LogKeyOverrides])
+
+	require.NoError(t, os.Remove(overridesFile))
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, TypedTestConfig{TestProperty: "TestValue1", TestIntegerWithMaxValue100: 7}, monitor.Get())
+	require.Len(t, events, 1)
+	require.Equal(t, []string{"/TestProperty"}, events[0].ChangedPaths)
+	require.Equal(t, []OptionOverride{
+		{Path: "/TestIntegerWithMaxValue100", Source: "env:ECS_OVERRIDE_TESTPROJECTTEAM_CONFIGNAME_TESTINTEGERWITHMAXVALUE100"},
+	}, events[0].Overrides)
+	require.Equal(t, events[0].Overrides, events[0].Metadata.Overrides)
+	require.Nil(t, ecsClientInstance.LastMetadata().Overrides)
+}
+
+// Tests that an override that can't be applied is logged and skipped
+func TestEcsGoClientInvalidOverride(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	logger := &structuredRecordingLogger{}
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, logger, WithEnvironmentOverrides())
+	ecsClientInstance.environ = func() []string {
+		return []string{"ECS_OVERRIDE_TESTPROJECTTEAM_CONFIGNAME_TESTPROPERTY__NESTED=1"}
+	}
+
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, "TestValue1", monitor.Get().TestProperty)
+
+	record := logger.find("ignored config override")
+	require.NotNil(t, record)
+	require.Equal(t, "env:ECS_OVERRIDE_TESTPROJECTTEAM_CONFIGNAME_TESTPROPERTY__NESTED", record.attrs[LogKeyOverrideSource])
+	require.NotContains(t, logger.find("Received ECS config").attrs, LogKeyOverrides)
+}
+
+// Tests that a change of the overrides file is applied to the last option received from ECS without another ECS event
+func TestEcsGoClientOverridesFileChange(t *testing.T) {
+	overridesFile := filepath.Join(t.TempDir(), "overrides.json")
+
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithOverridesFile(overridesFile), WithOverridesFilePollInterval(time.Millisecond))
+	defer ecsClientInstance.Close(context.Background())
+
+	events := make(chan OptionsUpdateEvent, 10)
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithOptionsUpdateEventCallbackFunc(func(event OptionsUpdateEvent) {
+			events <- event
+		}))
+	require.NoError(t, err)
+	require.Equal(t, "TestValue1", monitor.Get().TestProperty)
+
+	require.NoError(t, os.WriteFile(overridesFile, []byte(`{"TestProjectTeam": {"ConfigName": {"/TestProperty": "Pinned"}}}`), 0o600))
+
+	select {
+	case event := <-events:
+		require.NoError(t, event.Err)
+		require.Equal(t, []string{"/TestProperty"}, event.ChangedPaths)
+		require.Equal(t, []OptionOverride{{Path: "/TestProperty", Source: "file:" + overridesFile}}, event.Overrides)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the changed overrides file has not been applied")
+	}
+	require.Equal(t, "Pinned", monitor.Get().TestProperty)
+
+	require.NoError(t, os.Remove(overridesFile))
+
+	select {
+	case event := <-events:
+		require.NoError(t, event.Err)
+		require.Empty(t, event.Overrides)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the removed overrides file has not been applied")
+	}
+	require.Equal(t, "TestValue1", monitor.Get().TestProperty)
+	ecsConfigGetter.AssertNumberOfCalls(t, "GetConfig", 1)
+}
+
+// Tests that applying the changed overrides neither updates options monitors without an option from ECS nor counts as ECS update
+func TestEcsGoClientOverridesFileChangeMetrics(t *testing.T) {
+	overridesFile := filepath.Join(t.TempDir(), "overrides.json")
+
+	ecsConfigGetter := mockConfigGetter{}
+	failingCall := ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("ECS is unavailable"))
+
+	metrics := &updateRecordingMetrics{}
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithOverridesFile(overridesFile), WithMetricsRecorder(metrics))
+	defer ecsClientInstance.Close(context.Background())
+
+	testConfig := &TestConfig{}
+	_, err := ecsClientInstance.AddOptionsMonitorToEcsClient(testConfig, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, ErrFetchFailed)
+
+	require.NoError(t, os.WriteFile(overridesFile, []byte(`{"TestProjectTeam": {"ConfigName": {"/TestProperty": "Pinned"}}}`), 0o600))
+	ecsClientInstance.reapplyOptions()
+	require.Empty(t, metrics.updates)
+	require.Nil(t, ecsClientInstance.Current(testConfig))
+
+	failingCall.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+	ecsClientInstance.invokeOptionsUpdate(false)
+	require.Equal(t, []error{nil}, metrics.updates)
+	require.Equal(t, "Pinned", testConfig.TestProperty)
+
+	require.NoError(t, os.Remove(overridesFile))
+	ecsClientInstance.reapplyOptions()
+	require.Equal(t, "TestValue1", testConfig.TestProperty)
+	require.Equal(t, []error{nil}, metrics.updates)
+}
+
+// updateRecordingMetrics is a MetricsRecorder that records the errors of the observed updates
+type updateRecordingMetrics struct {
+	noopMetricsRecorder
+	updates []error
+}
+
+func (metrics *updateRecordingMetrics) ObserveUpdate(projectTeam string, optionName string, changed bool, err error) {
+	metrics.updates = append(metrics.updates, err)
+}
@@ -0,0 +1,227 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue replaces the redacted values of the configs in the log messages and options update events
+const RedactedValue = "[REDACTED]"
+
+// SecretPathsProvider can be implemented by an OptionsUpdateReceiver to redact values of its option in the log messages and options
+// update events. The typed monitors implement it based on the `ecs:"secret"` tags of their options type.
+type SecretPathsProvider interface {
+	// SecretPaths returns the JSON pointers (RFC 6901) of the secret values within the option, see WithRedactedPaths for the syntax
+	SecretPaths() []string
+}
+
+// WithRedactedPaths masks the values at the given JSON pointers (RFC 6901) of every option in the log messages and options update events.
+// The pointers are relative to the option, a "*" token matches any key or array index and the keys are matched case-insensitively,
+// like encoding/json does, e.g. "/Credentials/ApiKey" or "/Endpoints/*/Key". The snapshots returned by Current are not redacted.
+func WithRedactedPaths(patterns ...string) ClientOption {
+	return func(ecsClient *EcsClient) {
+		ecsClient.redactedPaths = append(ecsClient.redactedPaths, patterns...)
+	}
+}
+
+// WithChecksumOnlyLogging logs only the checksums of the received configs instead of their redacted values
+func WithChecksumOnlyLogging() ClientOption {
+	return func(ecsClient *EcsClient) {
+		ecsClient.checksumOnlyLogging = true
+	}
+}
+
+// redactor masks the values of a config at a set of JSON pointer patterns. A nil redactor leaves the configs unchanged.
+type redactor struct {
+	patterns [][]string
+}
+
+// newRedactor creates a redactor for the JSON pointer patterns, or returns nil if there are none
+func newRedactor(patterns []string) *redactor {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	redactor := &redactor{}
+	for _, pattern := range patterns {
+		tokens := []string{}
+		if pattern != "" {
+			for _, token := range strings.Split(strings.TrimPrefix(pattern, "/"), "/") {
+				tokens = append(tokens, unescapeJSONPointerToken(token))
+			}
+		}
+		redactor.patterns = append(redactor.patterns, tokens)
+	}
+
+	return redactor
+}
+
+// redact returns value with the values at the patterns replaced by RedactedValue. A value that can't be parsed is redacted completely,
+// since it is unknown where its secrets are.
+func (redactor *redactor) redact(value json.RawMessage) json.RawMessage {
+	if redactor == nil || value == nil {
+		return value
+	}
+
+	var document any
+	if err := json.Unmarshal(value, &document); err != nil {
+		return json.RawMessage(strconv.Quote(RedactedValue))
+	}
+
+	redacted, err := json.Marshal(redactor.redactValue(document, nil))
+	if err != nil {
+		return json.RawMessage(strconv.Quote(RedactedValue))
+	}
+
+	return redacted
+}
+
+// redactEvent returns event with its Previous and Current values redacted
+func (redactor *redactor) redactEvent(event OptionsUpdateEvent) OptionsUpdateEvent {
+	event.Previous = redactor.redact(event.Previous)
+	event.Current = redactor.redact(event.Current)
+	return event
+}
+
+// redactValue replaces the values below value at path that match a pattern by RedactedValue
+func (redactor *redactor) redactValue(value any, path []string) any {
+	if redactor.matches(path) {
+		return RedactedValue
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, child := range typedValue {
+			typedValue[key] = redactor.redactValue(child, append(path[:len(path):len(path)], key))
+		}
+	case []any:
+		for i, child := range typedValue {
+			typedValue[i] = redactor.redactValue(child, append(path[:len(path):len(path)], strconv.Itoa(i)))
+		}
+	}
+
+	return value
+}
+
+// covers reports whether the value at the JSON pointer is redacted, because it or one of its parents matches a pattern
+func (redactor *redactor) covers(pointer string) bool {
+	if redactor == nil {
+		return false
+	}
+
+	path := []string{}
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			path = append(path, unescapeJSONPointerToken(token))
+		}
+	}
+
+	for length := 0; length <= len(path); length++ {
+		if redactor.matches(path[:length]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches reports whether path matches one of the patterns
+func (redactor *redactor) matches(path []string) bool {
+	for _, pattern := range redactor.patterns {
+		if len(pattern) != len(path) {
+			continue
+		}
+
+		matched := true
+		for i, token := range pattern {
+			if token != "*" && !strings.EqualFold(token, path[i]) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unescapeJSONPointerToken reverts escapeJSONPointerToken
+func unescapeJSONPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// secretPaths returns the JSON pointer patterns of the fields of typ, and of the types nested in it, that are tagged with `ecs:"secret"`
+func secretPaths(typ reflect.Type) []string {
+	var paths []string
+	collectSecretPaths(typ, "", &paths, make(map[reflect.Type]bool))
+	return paths
+}
+
+// collectSecretPaths appends the secret paths of typ below path to paths. visiting guards against recursive types.
+func collectSecretPaths(typ reflect.Type, path string, paths *[]string, visiting map[reflect.Type]bool) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		collectSecretPaths(typ.Elem(), path+"/*", paths, visiting)
+	case reflect.Struct:
+		if visiting[typ] {
+			return
+		}
+		visiting[typ] = true
+		defer delete(visiting, typ)
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || (!field.IsExported() && !field.Anonymous) {
+				continue
+			}
+
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			// the fields of embedded structs are promoted to the embedding struct, like encoding/json does
+			if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+				collectSecretPaths(fieldType, path, paths, visiting)
+				continue
+			}
+
+			if !field.IsExported() {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			fieldPath := path + "/" + escapeJSONPointerToken(name)
+			if isSecretField(field) {
+				*paths = append(*paths, fieldPath)
+				continue
+			}
+
+			collectSecretPaths(field.Type, fieldPath, paths, visiting)
+		}
+	}
+}
+
+// isSecretField reports whether field is tagged with `ecs:"secret"`
+func isSecretField(field reflect.StructField) bool {
+	for _, option := range strings.Split(field.Tag.Get("ecs"), ",") {
+		if option == "secret" {
+			return true
+		}
+	}
+
+	return false
+}
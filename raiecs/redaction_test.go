@@ -0,0 +1,97 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type SecretTestEndpoint struct {
+	Url string `json:"Url"`
+	Key string `json:"Key" ecs:"secret"`
+}
+
+type SecretTestCredentials struct {
+	ApiKey string `ecs:"secret"`
+}
+
+type SecretTestConfig struct {
+	SecretTestCredentials
+
+	TestProperty string               `json:"TestProperty"`
+	Password     string               `json:"Password,omitempty" ecs:"secret"`
+	Endpoints    []SecretTestEndpoint `json:"Endpoints"`
+	Ignored      string               `json:"-" ecs:"secret"`
+}
+
+// Tests that the secret paths are derived from the ecs struct tags, including embedded and nested structs
+func TestSecretPaths(t *testing.T) {
+	paths := secretPaths(reflect.TypeOf(SecretTestConfig{}))
+	require.Equal(t, []string{"/ApiKey", "/Password", "/Endpoints/*/Key"}, paths)
+}
+
+// Tests that the values at the patterns are redacted, with wildcards and case-insensitive keys
+func TestRedactor(t *testing.T) {
+	redactor := newRedactor([]string{"/password", "/Endpoints/*/Key", "/a~1b"})
+
+	redacted := redactor.redact(json.RawMessage(`{"Password": "secret", "Endpoints": [{"Url": "u1", "Key": "k1"}, {"Url": "u2", "Key": {"nested": 1}}], "a/b": 1, "Other": "value"}`))
+	require.JSONEq(t, `{"Password": "[REDACTED]", "Endpoints": [{"Url": "u1", "Key": "[REDACTED]"}, {"Url": "u2", "Key": "[REDACTED]"}], "a/b": "[REDACTED]", "Other": "value"}`, string(redacted))
+
+	require.Equal(t, json.RawMessage(`"[REDACTED]"`), redactor.redact(json.RawMessage(`{"Password": `)))
+	require.Nil(t, redactor.redact(nil))
+	require.JSONEq(t, `"[REDACTED]"`, string(newRedactor([]string{""}).redact(json.RawMessage(`{"a": 1}`))))
+
+	require.Equal(t, json.RawMessage(`{"Password": "secret"}`), newRedactor(nil).redact(json.RawMessage(`{"Password": "secret"}`)))
+}
+
+// Tests that the secrets are masked in the log messages and options update events, while the changes of secrets are still reported
+func TestEcsGoClientRedaction(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {
+		"TestProperty": "TestValue1", "Password": "password1", "ApiKey": "apiKey1", "Endpoint": "https://internal.example"}}}`, nil)
+
+	logger := &structuredRecordingLogger{}
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, logger, WithRedactedPaths("/Endpoint"))
+
+	var events []OptionsUpdateEvent
+	monitor, err := AddTypedMonitor[SecretTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithOptionsUpdateEventCallbackFunc(func(event OptionsUpdateEvent) {
+			events = append(events, event)
+		}))
+	require.NoError(t, err)
+	require.Equal(t, "password1", monitor.Get().Password)
+
+	record := logger.find("Received ECS config")
+	require.NotNil(t, record)
+	require.JSONEq(t, `{"TestProperty": "TestValue1", "Password": "[REDACTED]", "ApiKey": "[REDACTED]", "Endpoint": "[REDACTED]"}`, record.attrs[LogKeyConfig])
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {
+		"TestProperty": "TestValue1", "Password": "password2", "ApiKey": "apiKey1", "Endpoint": "https://internal.example"}}}`, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Len(t, events, 1)
+	require.Equal(t, []string{"/Password"}, events[0].ChangedPaths)
+	require.NotContains(t, string(events[0].Previous), "password1")
+	require.NotContains(t, string(events[0].Current), "password2")
+	require.JSONEq(t, `{"TestProperty": "TestValue1", "Password": "[REDACTED]", "ApiKey": "[REDACTED]", "Endpoint": "[REDACTED]"}`, string(events[0].Current))
+	require.Contains(t, string(ecsClientInstance.Current(monitor).Value), "password2")
+
+	logger = &structuredRecordingLogger{}
+	ecsClientInstance = NewEcsClientFromConfigGetter(&ecsConfigGetter, logger, WithChecksumOnlyLogging())
+
+	monitor, err = AddTypedMonitor[SecretTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	record = logger.find("Received ECS config")
+	require.NotNil(t, record)
+	require.NotContains(t, record.attrs, LogKeyConfig)
+	require.Equal(t, ecsClientInstance.Current(monitor).CheckSum, record.attrs[LogKeyCheckSum])
+	require.Equal(t, ecsclientgowrapper.ECS_LOG_LEVEL_INFORMATION, record.logLevel)
+}
@@ -0,0 +1,137 @@
+package ecsgoclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaResourceURL is the URL the schema of an options monitor is compiled under
+const schemaResourceURL = "ecs:///options.schema.json"
+
+// SchemaProvider can be implemented by an OptionsUpdateReceiver to provide a JSON Schema (draft 2020-12 unless the schema declares
+// another draft with $schema) for its option. The option is validated against it after the defaults and overrides have been applied
+// and before OnOptionsUpdateReceived is called, and rejected with ErrValidationFailed and a *SchemaValidationError if it doesn't match.
+// The schema must be self-contained, references to other documents are not loaded. The typed monitors implement it for WithJSONSchema.
+type SchemaProvider interface {
+	// OptionsSchema returns the JSON Schema of the option, or nil if there is none
+	OptionsSchema() (json.RawMessage, error)
+}
+
+// WithJSONSchema sets the JSON Schema the options of the monitor are validated against, e.g. a file embedded with go:embed
+func WithJSONSchema(schema []byte) MonitorOption {
+	return func(options *monitorOptions) {
+		options.schema = schema
+	}
+}
+
+// SchemaViolation is a value of an option that doesn't match the JSON Schema of the options monitor
+type SchemaViolation struct {
+	// The JSON pointer (RFC 6901) of the invalid value within the option
+	Path string
+
+	// The JSON pointer of the violated keyword within the schema, e.g. "/properties/Timeout/maximum"
+	SchemaPath string
+
+	// Describes the violation. The messages of violations of secret values (see SecretPathsProvider and WithRedactedPaths) only name
+	// the violated keyword, as the messages of the JSON Schema validator may quote the invalid value, e.g. for "format".
+	Message string
+}
+
+// SchemaValidationError is the cause of the ErrValidationFailed errors of options that don't match the JSON Schema of their monitor.
+// Use errors.As to get it from the returned errors.
+type SchemaValidationError struct {
+	Violations []SchemaViolation
+}
+
+func (schemaValidationError *SchemaValidationError) Error() string {
+	violations := make([]string, 0, len(schemaValidationError.Violations))
+	for _, violation := range schemaValidationError.Violations {
+		violations = append(violations, fmt.Sprintf("'%v': %v", violation.Path, violation.Message))
+	}
+
+	return "options don't match the JSON schema: " + strings.Join(violations, "; ")
+}
+
+// setSchema compiles the JSON Schema of schemaProvider and sets it on the options monitor
+func (ecsOptionsMonitor *EcsOptionsMonitor) setSchema(schemaProvider SchemaProvider) error {
+	schema, err := schemaProvider.OptionsSchema()
+	if err == nil && schema == nil {
+		return nil
+	}
+
+	if err == nil {
+		ecsOptionsMonitor.schema, err = compileSchema(schema)
+	}
+
+	if err != nil {
+		return newEcsError(ErrInvalidConfig, ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName, fmt.Errorf("invalid options schema: %w", err))
+	}
+
+	return nil
+}
+
+// compileSchema compiles the JSON Schema schema without loading any referenced documents
+func compileSchema(schema []byte) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("loading the referenced schema '%v' is not supported", url)
+	}
+
+	if err := compiler.AddResource(schemaResourceURL, bytes.NewReader(schema)); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(schemaResourceURL)
+}
+
+// validateSchema validates the parsed option document against the schema of the options monitor, if it has one
+func (ecsOptionsMonitor *EcsOptionsMonitor) validateSchema(document any) error {
+	if ecsOptionsMonitor.schema == nil {
+		return nil
+	}
+
+	err := ecsOptionsMonitor.schema.Validate(document)
+
+	var validationError *jsonschema.ValidationError
+	if !errors.As(err, &validationError) {
+		return err
+	}
+
+	schemaValidationError := &SchemaValidationError{}
+	collectSchemaViolations(validationError, &schemaValidationError.Violations)
+	for i, violation := range schemaValidationError.Violations {
+		if ecsOptionsMonitor.redactor.covers(violation.Path) {
+			schemaValidationError.Violations[i].Message = secretViolationMessage(violation.SchemaPath)
+		}
+	}
+	return schemaValidationError
+}
+
+// secretViolationMessage returns the message of a violation of a secret value, which names only the keyword at schemaPath
+func secretViolationMessage(schemaPath string) string {
+	keyword := schemaPath[strings.LastIndex(schemaPath, "/")+1:]
+	return fmt.Sprintf("the secret value doesn't match '%v'", unescapeJSONPointerToken(keyword))
+}
+
+// collectSchemaViolations appends the leaves of the tree of validationError, which are the actual violations, to violations
+func collectSchemaViolations(validationError *jsonschema.ValidationError, violations *[]SchemaViolation) {
+	if len(validationError.Causes) == 0 {
+		*violations = append(*violations, SchemaViolation{
+			Path:       validationError.InstanceLocation,
+			SchemaPath: validationError.KeywordLocation,
+			Message:    validationError.Message,
+		})
+		return
+	}
+
+	for _, cause := range validationError.Causes {
+		collectSchemaViolations(cause, violations)
+	}
+}
@@ -0,0 +1,103 @@
+package ecsgoclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const typedTestConfigSchema = `{
+	"type": "object",
+	"properties": {
+		"TestProperty": {"type": "string", "minLength": 1},
+		"TestIntegerWithMaxValue100": {"type": "integer", "maximum": 100}
+	},
+	"required": ["TestProperty"]
+}`
+
+// Tests that options that don't match the JSON schema are rejected with all violations and the last accepted options are kept
+func TestTypedMonitorJSONSchema(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestProperty": "TestValue1", "TestIntegerWithMaxValue100": 5}}}`, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	var updateErrors []error
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName", WithJSONSchema([]byte(typedTestConfigSchema)),
+		WithUpdateEventCallbackFunc(func(optionsUpdateError error) {
+			updateErrors = append(updateErrors, optionsUpdateError)
+		}))
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "TestValue1", TestIntegerWithMaxValue100: 5}, monitor.Get())
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestProperty": "", "TestIntegerWithMaxValue100": 101}}}`, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, TypedTestConfig{TestProperty: "TestValue1", TestIntegerWithMaxValue100: 5}, monitor.Get())
+	require.Len(t, updateErrors, 1)
+	require.ErrorIs(t, updateErrors[0], ErrValidationFailed)
+
+	var schemaValidationError *SchemaValidationError
+	require.True(t, errors.As(updateErrors[0], &schemaValidationError))
+	require.ElementsMatch(t, []SchemaViolation{
+		{Path: "/TestProperty", SchemaPath: "/properties/TestProperty/minLength", Message: "length must be >= 1, but got 0"},
+		{Path: "/TestIntegerWithMaxValue100", SchemaPath: "/properties/TestIntegerWithMaxValue100/maximum", Message: "must be <= 100 but found 101"},
+	}, schemaValidationError.Violations)
+}
+
+// Tests that the defaults are validated together with the option and that an invalid schema fails adding the monitor
+func TestTypedMonitorJSONSchemaWithDefaults(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"TestIntegerWithMaxValue100": 7}}}`, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName", WithJSONSchema([]byte(typedTestConfigSchema)),
+		WithDefaultJSON([]byte(`{"TestProperty": "DefaultValue"}`)))
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "DefaultValue", TestIntegerWithMaxValue100: 7}, monitor.Get())
+
+	_, err = AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "OtherConfigName", WithJSONSchema([]byte(typedTestConfigSchema)))
+	require.ErrorIs(t, err, ErrOptionNotFound)
+
+	_, err = AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName", WithJSONSchema([]byte(`{"type": 1}`)))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName", WithJSONSchema([]byte(`{"$ref": "https://example.com/schema.json"}`)))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+// Tests that the violations of secret values don't contain the invalid value
+func TestTypedMonitorJSONSchemaSecretViolation(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(`{"TestProjectTeam": {"ConfigName": {"Url": "not a uri", "Password": "hunter2"}}}`, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	// format is only asserted by default for drafts before 2019-09
+	_, err := AddTypedMonitor[secretTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName", WithJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"Url": {"type": "string", "format": "uri"},
+			"Password": {"type": "string", "format": "email"}
+		}
+	}`)))
+	require.ErrorIs(t, err, ErrValidationFailed)
+	require.NotContains(t, err.Error(), "hunter2")
+
+	var schemaValidationError *SchemaValidationError
+	require.True(t, errors.As(err, &schemaValidationError))
+	require.ElementsMatch(t, []SchemaViolation{
+		{Path: "/Url", SchemaPath: "/properties/Url/format", Message: "'not a uri' is not valid 'uri'"},
+		{Path: "/Password", SchemaPath: "/properties/Password/format", Message: "the secret value doesn't match 'format'"},
+	}, schemaValidationError.Violations)
+}
+
+type secretTestConfig struct {
+	Url      string `json:"Url"`
+	Password string `json:"Password" ecs:"secret"`
+}
@@ -0,0 +1,194 @@
+package ecsgoclient
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// WithSnapshotDirectory persists every accepted option to directory and, if the initial config of an options monitor can't be fetched,
+// loads the last-known-good option from there instead. The option is persisted as received from ECS, so the defaults and overrides
+// of the loading run are applied to it again. The loaded snapshot is reported as stale through OptionsSnapshot.Stale and
+// MonitorHealth.Stale until the first successful update. The snapshots contain the unredacted options, so the directory should
+// only be readable by the service.
+func WithSnapshotDirectory(directory string) ClientOption {
+	return func(ecsClient *EcsClient) {
+		ecsClient.snapshotDirectory = directory
+	}
+}
+
+// persistedSnapshot is the content of a snapshot file. Value is the option as received from ECS without the defaults and overrides,
+// and CheckSum is its checksum.
+type persistedSnapshot struct {
+	ProjectTeam string          `json:"projectTeam"`
+	OptionName  string          `json:"optionName"`
+	Value       json.RawMessage `json:"value"`
+	Missing     bool            `json:"missing,omitempty"`
+	CheckSum    string          `json:"checkSum"`
+	ConfigID    string          `json:"configId,omitempty"`
+	SavedAt     time.Time       `json:"savedAt"`
+}
+
+// snapshotFilePath returns the path of the snapshot file of optionName of projectTeam in directory.
+// Dots are escaped as well, so the separator is unambiguous and the names can't refer to other directories.
+func snapshotFilePath(directory string, projectTeam string, optionName string) string {
+	escape := func(name string) string {
+		return strings.ReplaceAll(url.PathEscape(name), ".", "%2E")
+	}
+
+	return filepath.Join(directory, escape(projectTeam)+"."+escape(optionName)+".json")
+}
+
+// persistSnapshot writes the parsed option ecsOption of the accepted update of ecsOptionsMonitor to the snapshot directory, if there is one.
+// found is false if the option is missing in ECS. Failures are only logged, as they must not fail the update.
+func (ecsClient *EcsClient) persistSnapshot(ecsOptionsMonitor *EcsOptionsMonitor, ecsOption any, found bool, configID string) {
+	if ecsClient.snapshotDirectory == "" {
+		return
+	}
+
+	value, err := json.Marshal(ecsOption)
+	var checkSum string
+	if err == nil {
+		checkSum, err = getCheckSum(value)
+	}
+
+	var data []byte
+	if err == nil {
+		data, err = json.Marshal(persistedSnapshot{
+			ProjectTeam: ecsOptionsMonitor.projectTeam,
+			OptionName:  ecsOptionsMonitor.optionName,
+			Value:       value,
+			Missing:     !found,
+			CheckSum:    checkSum,
+			ConfigID:    configID,
+			SavedAt:     ecsClient.now(),
+		})
+	}
+	if err == nil {
+		err = writeFileAtomic(snapshotFilePath(ecsClient.snapshotDirectory, ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName), data)
+	}
+
+	if err != nil {
+		ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, "persisting the config snapshot failed",
+			append(optionLogAttrs(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName), errorLogAttrs(err)...)...)
+	}
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it to path, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+	}
+
+	return err
+}
+
+// readSnapshot reads the snapshot of optionName of projectTeam from directory and verifies its checksum
+func readSnapshot(directory string, projectTeam string, optionName string) (*persistedSnapshot, error) {
+	data, err := os.ReadFile(snapshotFilePath(directory, projectTeam, optionName))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot persistedSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	checkSum, err := getCheckSum(snapshot.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot.ProjectTeam != projectTeam || snapshot.OptionName != optionName || checkSum != snapshot.CheckSum {
+		return nil, errors.New("the snapshot doesn't match its option or checksum")
+	}
+
+	return &snapshot, nil
+}
+
+// loadLastKnownGood applies the persisted snapshot to ecsOptionsMonitor after its initial config could not be fetched because of fetchErr.
+// Returns an error if there is no valid snapshot or the options rejected it.
+func (ecsClient *EcsClient) loadLastKnownGood(ecsOptionsMonitor *EcsOptionsMonitor, fetchErr error) error {
+	persisted, err := readSnapshot(ecsClient.snapshotDirectory, ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName)
+	if err != nil {
+		return err
+	}
+
+	ecsOptionsMonitor.updateMutex.Lock()
+	defer ecsOptionsMonitor.updateMutex.Unlock()
+
+	if persisted.Missing && !ecsOptionsMonitor.hasDefaults {
+		return newEcsError(ErrOptionNotFound, ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName, errors.New("the snapshot has no option and there are no defaults"))
+	}
+
+	var ecsOption any
+	if err := json.Unmarshal(persisted.Value, &ecsOption); err != nil {
+		return err
+	}
+
+	optionConfig, appliedOverrides := ecsClient.effectiveOption(ecsOptionsMonitor, ecsOption, !persisted.Missing)
+	jsonOpts, err := json.Marshal(optionConfig)
+	if err != nil {
+		return err
+	}
+
+	checkSum, err := getCheckSum(jsonOpts)
+	if err != nil {
+		return err
+	}
+
+	if err := ecsOptionsMonitor.validateSchema(optionConfig); err != nil {
+		return err
+	}
+
+	if err := callOnOptionsUpdateReceived(ecsOptionsMonitor.options, jsonOpts); err != nil {
+		return err
+	}
+
+	ecsOptionsMonitor.snapshot.Store(&OptionsSnapshot{Value: jsonOpts, CheckSum: checkSum, ConfigID: persisted.ConfigID, Stale: true})
+	ecsOptionsMonitor.lastReceived = &receivedOption{
+		value:    ecsOption,
+		found:    !persisted.Missing,
+		metadata: ConfigMetadata{ConfigIDs: map[string]string{ecsOptionsMonitor.projectTeam: persisted.ConfigID}},
+		stale:    true,
+	}
+	ecsOptionsMonitor.markStale()
+
+	attrs := append(optionLogAttrs(ecsOptionsMonitor.projectTeam, ecsOptionsMonitor.optionName),
+		slog.String(LogKeyCheckSum, checkSum), slog.String(LogKeyConfigID, persisted.ConfigID), slog.Time(LogKeySavedAt, persisted.SavedAt))
+	if len(appliedOverrides) > 0 {
+		attrs = append(attrs, slog.String(LogKeyOverrides, overridesLogValue(appliedOverrides)))
+	}
+	attrs = append(attrs, errorLogAttrs(fetchErr)...)
+	ecsclientgowrapper.LogAttrs(ecsClient.logger, ecsclientgowrapper.ECS_LOG_LEVEL_WARNING, "using the last-known-good config snapshot", attrs...)
+
+	return nil
+}
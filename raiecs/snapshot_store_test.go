@@ -0,0 +1,115 @@
+package ecsgoclient
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the snapshot file names can't escape the snapshot directory and don't collide
+func TestSnapshotFilePath(t *testing.T) {
+	require.Equal(t, filepath.Join("dir", "TestProjectTeam.ConfigName.json"), snapshotFilePath("dir", "TestProjectTeam", "ConfigName"))
+	require.Equal(t, filepath.Join("dir", "%2E%2E.%2Fetc%2Fpasswd.json"), snapshotFilePath("dir", "..", "/etc/passwd"))
+	require.NotEqual(t, snapshotFilePath("dir", "a.b", "c"), snapshotFilePath("dir", "a", "b.c"))
+}
+
+// Tests that accepted options are persisted and loaded as stale last-known-good snapshot if the initial fetch fails
+func TestEcsGoClientLastKnownGoodSnapshot(t *testing.T) {
+	snapshotDirectory := t.TempDir()
+
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithSnapshotDirectory(snapshotDirectory))
+
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	persisted := ecsClientInstance.Current(monitor)
+	require.Equal(t, "P-D-1129197-1-172", persisted.ConfigID)
+	require.False(t, persisted.Stale)
+
+	entries, err := os.ReadDir(snapshotDirectory)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "TestProjectTeam.ConfigName.json", entries[0].Name())
+
+	failingConfigGetter := mockConfigGetter{}
+	failingCall := failingConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("ECS is unavailable"))
+
+	restartedClient := NewEcsClientFromConfigGetter(&failingConfigGetter, &NoopLogger{}, WithSnapshotDirectory(snapshotDirectory))
+
+	restartedMonitor, err := AddTypedMonitor[TestConfig](restartedClient, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, "TestValue1", restartedMonitor.Get().TestProperty)
+
+	snapshot := restartedClient.Current(restartedMonitor)
+	require.True(t, snapshot.Stale)
+	require.Equal(t, persisted.CheckSum, snapshot.CheckSum)
+	require.Equal(t, persisted.ConfigID, snapshot.ConfigID)
+
+	health := restartedClient.Health()
+	require.True(t, health[0].Stale)
+	require.Equal(t, 1, health[0].ConsecutiveFailures)
+	require.ErrorIs(t, health[0].LastError, ErrFetchFailed)
+
+	_, err = AddTypedMonitor[TestConfig](restartedClient, "TestProjectTeam", "OtherConfigName")
+	require.ErrorIs(t, err, ErrFetchFailed)
+
+	failingCall.Unset()
+	failingConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	restartedClient.invokeOptionsUpdate(false)
+	require.False(t, restartedClient.Current(restartedMonitor).Stale)
+	require.False(t, restartedClient.Health()[0].Stale)
+}
+
+// Tests that a snapshot that doesn't match its checksum is not loaded
+func TestEcsGoClientCorruptSnapshot(t *testing.T) {
+	snapshotDirectory := t.TempDir()
+	require.NoError(t, os.WriteFile(snapshotFilePath(snapshotDirectory, "TestProjectTeam", "ConfigName"),
+		[]byte(`{"projectTeam": "TestProjectTeam", "optionName": "ConfigName", "value": {"TestProperty": "Tampered"}, "checkSum": "invalid"}`), 0o600))
+
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("ECS is unavailable"))
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithSnapshotDirectory(snapshotDirectory))
+
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, ErrFetchFailed)
+	require.Nil(t, monitor.Current())
+}
+
+// Tests that the snapshot holds the option as received from ECS, so overrides removed before the restart aren't loaded again
+func TestEcsGoClientSnapshotWithoutOverrides(t *testing.T) {
+	snapshotDirectory := t.TempDir()
+
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithSnapshotDirectory(snapshotDirectory), WithEnvironmentOverrides())
+	ecsClientInstance.environ = func() []string {
+		return []string{"ECS_OVERRIDE_TESTPROJECTTEAM_CONFIGNAME_TESTPROPERTY=Pinned"}
+	}
+
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, "Pinned", monitor.Get().TestProperty)
+
+	failingConfigGetter := mockConfigGetter{}
+	failingConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("ECS is unavailable"))
+
+	restartedClient := NewEcsClientFromConfigGetter(&failingConfigGetter, &NoopLogger{}, WithSnapshotDirectory(snapshotDirectory), WithEnvironmentOverrides())
+	restartedClient.environ = func() []string {
+		return nil
+	}
+
+	restartedMonitor, err := AddTypedMonitor[TypedTestConfig](restartedClient, "TestProjectTeam", "ConfigName",
+		WithDefaultJSON([]byte(`{"TestIntegerWithMaxValue100": 50, "TestProperty": "Default"}`)))
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "TestValue1", TestIntegerWithMaxValue100: 1}, restartedMonitor.Get())
+	require.True(t, restartedClient.Current(restartedMonitor).Stale)
+}
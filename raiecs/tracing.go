@@ -0,0 +1,69 @@
+package ecsgoclient
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/raiecs/ecsclientgowrapper"
+)
+
+// tracerName is the instrumentation name of the tracer of the EcsClient
+const tracerName = "github.com/raiecs"
+
+// Names of the spans created by the EcsClient
+const (
+	SpanNameUpdate   = "ecs.update"
+	SpanNameFetch    = "ecs.fetch"
+	SpanNameParse    = "ecs.parse"
+	SpanNameApply    = "ecs.apply"
+	SpanNameDispatch = "ecs.dispatch"
+)
+
+// Attribute keys of the spans created by the EcsClient
+const (
+	AttributeProjectTeam = attribute.Key("ecs.project_team")
+	AttributeOptionName  = attribute.Key("ecs.option_name")
+	AttributeETag        = attribute.Key("ecs.etag")
+	AttributeConfigID    = attribute.Key("ecs.config_id")
+	AttributeEventType   = attribute.Key("ecs.event_type")
+	AttributeInitial     = attribute.Key("ecs.initial")
+	AttributeChanged     = attribute.Key("ecs.changed")
+	AttributeStale       = attribute.Key("ecs.stale")
+)
+
+// WithTracerProvider sets the TracerProvider the EcsClient creates the spans of its config fetches and updates with.
+// Without it, no spans are recorded.
+func WithTracerProvider(tracerProvider trace.TracerProvider) ClientOption {
+	return func(ecsClient *EcsClient) {
+		if tracerProvider != nil {
+			ecsClient.tracer = tracerProvider.Tracer(tracerName)
+		}
+	}
+}
+
+// noopTracer is the tracer of EcsClients without a TracerProvider
+func noopTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+// optionAttributes returns the span attributes identifying the options monitor of optionName of projectTeam
+func optionAttributes(projectTeam string, optionName string) []attribute.KeyValue {
+	return []attribute.KeyValue{AttributeProjectTeam.String(projectTeam), AttributeOptionName.String(optionName)}
+}
+
+// eventTypeAttribute returns the span attribute of an event type of the ecs C library
+func eventTypeAttribute(eventType ecsclientgowrapper.ECS_EVENT_TYPE) attribute.KeyValue {
+	return AttributeEventType.String(eventType.String())
+}
+
+// endSpan records err on span, if it is not nil, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,101 @@
+package ecsgoclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// findSpans returns the recorded spans with the given name
+func findSpans(spans tracetest.SpanStubs, name string) tracetest.SpanStubs {
+	var found tracetest.SpanStubs
+	for _, span := range spans {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+
+	return found
+}
+
+// Tests that a config update creates the fetch, parse, apply and dispatch spans below the update span
+func TestEcsGoClientTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithTracerProvider(tracerProvider))
+
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithUpdateEventCallbackFunc(func(optionsUpdateError error) {}))
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	exporter.Reset()
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 5)
+
+	updateSpan := findSpans(spans, SpanNameUpdate)[0]
+	require.False(t, updateSpan.Parent.IsValid())
+	require.Contains(t, updateSpan.Attributes, AttributeETag.String("someEtag"))
+
+	for _, name := range []string{SpanNameFetch, SpanNameParse, SpanNameApply} {
+		span := findSpans(spans, name)[0]
+		require.Equal(t, updateSpan.SpanContext.SpanID(), span.Parent.SpanID(), name)
+		require.Equal(t, codes.Unset, span.Status.Code, name)
+	}
+
+	applySpan := findSpans(spans, SpanNameApply)[0]
+	require.Subset(t, applySpan.Attributes, []attribute.KeyValue{
+		AttributeProjectTeam.String("TestProjectTeam"),
+		AttributeOptionName.String("ConfigName"),
+		AttributeETag.String("someEtag"),
+		AttributeConfigID.String("P-D-1129197-1-172"),
+		AttributeChanged.Bool(true),
+	})
+
+	dispatchSpan := findSpans(spans, SpanNameDispatch)[0]
+	require.Equal(t, applySpan.SpanContext.SpanID(), dispatchSpan.Parent.SpanID())
+	require.Equal(t, updateSpan.SpanContext.TraceID(), dispatchSpan.SpanContext.TraceID())
+	require.Subset(t, dispatchSpan.Attributes, []attribute.KeyValue{
+		AttributeProjectTeam.String("TestProjectTeam"),
+		AttributeOptionName.String("ConfigName"),
+	})
+}
+
+// Tests that a failed GetConfigContext call records the error on its fetch span
+func TestEcsGoClientTracingFetchError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return("", errors.New("fetch failed"))
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{}, WithTracerProvider(tracerProvider))
+
+	_, err := ecsClientInstance.GetConfigContext(context.Background(), ecsclientgowrapper.EcsRequestIdentifiers{})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, SpanNameFetch, spans[0].Name)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, "fetch failed", spans[0].Status.Description)
+}
@@ -0,0 +1,159 @@
+package ecsgoclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Validator can be implemented by an options type to validate a received config before it is accepted
+type Validator interface {
+	Validate() error
+}
+
+// MonitorOption configures an options monitor that is added to the ecsClient
+type MonitorOption func(*monitorOptions)
+
+// monitorOptions are the settings that can be provided through MonitorOption
+type monitorOptions struct {
+	updateEventCallbacks []monitorCallback
+
+	// defaultOptions returns the JSON of the default options, nil if the monitor has no defaults
+	defaultOptions func() (json.RawMessage, error)
+
+	// schema is the JSON Schema the options are validated against, nil if they are not
+	schema json.RawMessage
+}
+
+// monitorCallback is an update callback provided through MonitorOption
+type monitorCallback struct {
+	callback EcsOptionsUpdateEventCallbackFunc
+	opts     []SubscriptionOption
+}
+
+// WithUpdateEventCallbackFunc registers a callback func on the monitor that is called whenever a config update has been received
+func WithUpdateEventCallbackFunc(configUpdateEvent EcsUpdateEventCallbackFunc, opts ...SubscriptionOption) MonitorOption {
+	return WithMetadataUpdateEventCallbackFunc(func(metadata ConfigMetadata, optionsUpdateError error) {
+		configUpdateEvent(optionsUpdateError)
+	}, opts...)
+}
+
+// WithMetadataUpdateEventCallbackFunc registers a callback func on the monitor that is called with the ECS config metadata whenever a config
+// update has been received
+func WithMetadataUpdateEventCallbackFunc(configUpdateEvent EcsMetadataUpdateEventCallbackFunc, opts ...SubscriptionOption) MonitorOption {
+	return WithOptionsUpdateEventCallbackFunc(func(event OptionsUpdateEvent) {
+		configUpdateEvent(event.Metadata, event.Err)
+	}, opts...)
+}
+
+// WithOptionsUpdateEventCallbackFunc registers a callback func on the monitor that receives the details of each config update
+func WithOptionsUpdateEventCallbackFunc(configUpdateEvent EcsOptionsUpdateEventCallbackFunc, opts ...SubscriptionOption) MonitorOption {
+	return func(options *monitorOptions) {
+		options.updateEventCallbacks = append(options.updateEventCallbacks, monitorCallback{callback: configUpdateEvent, opts: opts})
+	}
+}
+
+// Monitor holds the latest accepted value of an ECS option decoded into T. Every accepted config is published as a new
+// immutable snapshot, so readers never observe a partially applied update.
+type Monitor[T any] struct {
+	value          atomic.Pointer[T]
+	unsubscribe    UnsubscribeFunc
+	defaultOptions func() (json.RawMessage, error)
+	schema         json.RawMessage
+}
+
+// Get returns a shallow copy of the latest accepted options value. It is safe to call concurrently with config updates.
+// The maps, slices and pointers of the copy are shared with the snapshot and all other readers, so they must not be modified.
+func (monitor *Monitor[T]) Get() T {
+	if current := monitor.value.Load(); current != nil {
+		return *current
+	}
+
+	var empty T
+	return empty
+}
+
+// Current returns the snapshot of the latest accepted options value, or nil if no config has been accepted yet.
+// The snapshot is shared between all readers and must not be modified.
+func (monitor *Monitor[T]) Current() *T {
+	return monitor.value.Load()
+}
+
+// Remove removes the monitor and its callbacks from the ecsClient, the value is not updated anymore afterwards
+func (monitor *Monitor[T]) Remove() {
+	if monitor.unsubscribe != nil {
+		monitor.unsubscribe()
+	}
+}
+
+// OnOptionsUpdateReceived decodes the received config into T, validates it if T implements Validator and stores it
+func (monitor *Monitor[T]) OnOptionsUpdateReceived(bytes []byte) error {
+	var parsedOptions T
+	if err := json.Unmarshal(bytes, &parsedOptions); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal options, err: %v", ErrInvalidConfig, err)
+	}
+
+	if validator, ok := any(&parsedOptions).(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+
+	monitor.value.Store(&parsedOptions)
+	return nil
+}
+
+// SecretPaths returns the paths of the fields of T that are tagged with `ecs:"secret"`, so their values are redacted in the log messages
+// and options update events
+func (monitor *Monitor[T]) SecretPaths() []string {
+	return secretPaths(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// DefaultOptions returns the default options set with WithDefaultValue or WithDefaultJSON, or nil if there are none
+func (monitor *Monitor[T]) DefaultOptions() (json.RawMessage, error) {
+	if monitor.defaultOptions == nil {
+		return nil, nil
+	}
+
+	return monitor.defaultOptions()
+}
+
+// OptionsSchema returns the JSON Schema set with WithJSONSchema, or nil if there is none
+func (monitor *Monitor[T]) OptionsSchema() (json.RawMessage, error) {
+	return monitor.schema, nil
+}
+
+// AddTypedMonitor adds a monitor for the option optionName of projectTeam to the ecsClient. The config is decoded into T and,
+// if T implements Validator, validated before it gets accepted. If the initial config can't be fetched or is rejected, the monitor
+// stays registered for later updates and is returned together with the error; its Current() is nil until the first accepted config.
+func AddTypedMonitor[T any](ecsClient *EcsClient, projectTeam string, optionName string, opts ...MonitorOption) (*Monitor[T], error) {
+	return AddTypedMonitorContext[T](context.Background(), ecsClient, projectTeam, optionName, opts...)
+}
+
+// AddTypedMonitorContext is like AddTypedMonitor, but the initial config load is bounded by ctx. If ctx is done before the initial
+// config has been fetched, no monitor is returned.
+func AddTypedMonitorContext[T any](ctx context.Context, ecsClient *EcsClient, projectTeam string, optionName string, opts ...MonitorOption) (*Monitor[T], error) {
+	var options monitorOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	monitor := &Monitor[T]{defaultOptions: options.defaultOptions, schema: options.schema}
+	// the monitor is only unregistered if it couldn't be registered or ctx is done, a failed initial update is returned with the monitor
+	unsubscribe, initialUpdateError := ecsClient.AddOptionsMonitorContext(ctx, monitor, projectTeam, optionName)
+	if unsubscribe == nil {
+		return nil, initialUpdateError
+	}
+	monitor.unsubscribe = unsubscribe
+
+	for _, configUpdateEvent := range options.updateEventCallbacks {
+		if _, err := ecsClient.RegisterOptionsUpdateEventCallbackFunc(monitor, configUpdateEvent.callback, configUpdateEvent.opts...); err != nil {
+			unsubscribe()
+			return nil, err
+		}
+	}
+
+	return monitor, initialUpdateError
+}
@@ -0,0 +1,202 @@
+package ecsgoclient
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/raiecs/ecsclientgowrapper"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TypedTestConfig struct {
+	TestProperty string `json:"TestProperty"`
+
+	TestIntegerWithMaxValue100 int `json:"TestIntegerWithMaxValue100"`
+}
+
+// Test receiving the initial config through a typed monitor
+func TestTypedMonitorInitialConfigReceived(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	monitor, err := AddTypedMonitor[TypedTestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+	require.Equal(t, TypedTestConfig{TestProperty: "TestValue1", TestIntegerWithMaxValue100: 1}, monitor.Get())
+}
+
+// Tests that the Validate func of the options type is used to reject invalid configs and that the monitor stays registered
+func TestTypedMonitorInvalidInitialConfigReceived(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(invalidConfigUpdate, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.ErrorIs(t, err, ErrValidationFailed)
+	require.NotNil(t, monitor)
+	require.Nil(t, monitor.Current())
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	require.Equal(t, "TestValue1", monitor.Get().TestProperty)
+}
+
+// Tests that a config update is applied to the typed monitor and the registered callbacks are called
+func TestTypedMonitorConfigUpdateReceived(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	configUpdateCounter := 0
+	var optionsUpdateError error
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithUpdateEventCallbackFunc(func(innerOptionsUpdateError error) {
+			optionsUpdateError = innerOptionsUpdateError
+			if innerOptionsUpdateError == nil {
+				configUpdateCounter++
+			}
+		}))
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.NoError(t, optionsUpdateError)
+	require.Equal(t, 1, configUpdateCounter)
+	require.Equal(t, "TestValue2", monitor.Get().TestProperty)
+	require.Equal(t, 2, monitor.Get().TestIntegerWithMaxValue100)
+
+	configUpdateEvent2.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(invalidConfigUpdate, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Error(t, optionsUpdateError)
+	require.Equal(t, 1, configUpdateCounter)
+	require.Equal(t, "TestValue2", monitor.Get().TestProperty)
+}
+
+// alternatingConfigGetter returns validConfigUpdate1 and validConfigUpdate2 in turns and can be called concurrently
+type alternatingConfigGetter struct {
+	calls atomic.Int64
+}
+
+func (alternatingConfigGetter *alternatingConfigGetter) GetConfig(ecsRequestIdentifiers ecsclientgowrapper.EcsRequestIdentifiers) (string, error) {
+	if alternatingConfigGetter.calls.Add(1)%2 == 0 {
+		return validConfigUpdate2, nil
+	}
+
+	return validConfigUpdate1, nil
+}
+
+// Tests that concurrent config updates and reads only ever observe complete snapshots
+func TestTypedMonitorConcurrentUpdates(t *testing.T) {
+	ecsClientInstance := NewEcsClientFromConfigGetter(&alternatingConfigGetter{}, &NoopLogger{})
+
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ecsClientInstance.invokeOptionsUpdate(false)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				current := monitor.Current()
+				require.Equal(t, fmt.Sprintf("TestValue%d", current.TestIntegerWithMaxValue100), current.TestProperty)
+
+				snapshot := ecsClientInstance.Current(monitor)
+				require.NotNil(t, snapshot)
+				require.NotEmpty(t, snapshot.CheckSum)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Tests that the options update events contain the previous and new config and the changed paths
+func TestTypedMonitorOptionsUpdateEvents(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	var events []OptionsUpdateEvent
+	_, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithOptionsUpdateEventCallbackFunc(func(event OptionsUpdateEvent) {
+			events = append(events, event)
+		}))
+	require.NoError(t, err)
+
+	configUpdateEvent1.Unset()
+	configUpdateEvent2 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Len(t, events, 1)
+	require.NoError(t, events[0].Err)
+	require.Equal(t, "TestProjectTeam", events[0].ProjectTeam)
+	require.Equal(t, "ConfigName", events[0].OptionName)
+	require.Equal(t, ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED, events[0].EventType)
+	require.JSONEq(t, `{"TestProperty": "TestValue1", "TestIntegerWithMaxValue100": 1}`, string(events[0].Previous))
+	require.JSONEq(t, `{"TestProperty": "TestValue2", "TestIntegerWithMaxValue100": 2}`, string(events[0].Current))
+	require.Equal(t, []string{"/TestIntegerWithMaxValue100", "/TestProperty"}, events[0].ChangedPaths)
+	require.Equal(t, "P-D-1129197-1-172", events[0].Metadata.ConfigID("TestProjectTeam"))
+
+	configUpdateEvent2.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(invalidConfigUpdate, nil)
+
+	ecsClientInstance.handleEvent(EcsEvent{Type: ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE})
+	waitForDispatch(ecsClientInstance)
+	require.Len(t, events, 2)
+	require.ErrorIs(t, events[1].Err, ErrValidationFailed)
+	require.Equal(t, ecsclientgowrapper.ECS_EVENT_CONFIGURATION_CHANGED_FROM_CACHE, events[1].EventType)
+	require.Equal(t, events[0].Current, events[1].Previous)
+	require.Nil(t, events[1].Current)
+	require.Empty(t, events[1].ChangedPaths)
+}
+
+// Tests that a removed typed monitor keeps its last value but is not updated anymore
+func TestTypedMonitorRemove(t *testing.T) {
+	ecsConfigGetter := mockConfigGetter{}
+	configUpdateEvent1 := ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate1, nil)
+
+	ecsClientInstance := NewEcsClientFromConfigGetter(&ecsConfigGetter, &NoopLogger{})
+
+	configUpdateCounter := 0
+	monitor, err := AddTypedMonitor[TestConfig](ecsClientInstance, "TestProjectTeam", "ConfigName",
+		WithUpdateEventCallbackFunc(func(innerOptionsUpdateError error) {
+			configUpdateCounter++
+		}))
+	require.NoError(t, err)
+
+	monitor.Remove()
+	monitor.Remove()
+
+	configUpdateEvent1.Unset()
+	ecsConfigGetter.On("GetConfig", mock.Anything).Return(validConfigUpdate2, nil)
+
+	ecsClientInstance.invokeOptionsUpdate(false)
+	waitForDispatch(ecsClientInstance)
+	require.Equal(t, 0, configUpdateCounter)
+	require.Equal(t, "TestValue1", monitor.Get().TestProperty)
+	require.ErrorIs(t, ecsClientInstance.RemoveOptionsMonitor(monitor), ErrMonitorNotFound)
+}